@@ -0,0 +1,284 @@
+package snowflake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BlockSource issues blocks of unique IDs, e.g. a remote issuer which holds the node id
+type BlockSource interface {
+	// Block returns up to n new IDs, it must never return an ID twice
+	Block(n int) ([]uint64, error)
+}
+
+// BufferedGenerator is a Generator running a background refill which has to be stopped by invoking Close()
+type BufferedGenerator interface {
+	Generator
+	Close() error
+}
+
+type bufferedGeneratorImpl struct {
+	source        BlockSource
+	low           int
+	high          int
+	codec         Codec
+	failFast      bool
+	waitTimeout   time.Duration
+	retryInterval time.Duration
+
+	buffer  chan uint64
+	refill  chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+
+	lock        sync.Mutex
+	sourceErr   error
+	unavailable chan struct{}
+}
+
+type BufferedOption func(*bufferedGeneratorImpl) error
+
+// WithBufferLayout sets the layout used to decode IDs of the source counting from the UNIX epoch, see WithBufferCodec
+// for sources using another epoch. By default DefaultLayout
+func WithBufferLayout(layout Layout) BufferedOption {
+	return func(impl *bufferedGeneratorImpl) error {
+		if err := layout.Validate(); err != nil {
+			return err
+		}
+		impl.codec.Layout = layout
+		return nil
+	}
+}
+
+// WithBufferCodec sets the codec used to decode IDs of the source, e.g. the NewCodec of the options of the
+// issuing generator. By default DefaultLayout and the UNIX epoch
+func WithBufferCodec(codec Codec) BufferedOption {
+	return func(impl *bufferedGeneratorImpl) error {
+		if err := codec.Layout.Validate(); err != nil {
+			return err
+		}
+		impl.codec = codec
+		return nil
+	}
+}
+
+// WithBufferFailFast makes Next return ErrSourceUnavailable as soon as the buffer is empty and the last refill failed.
+// By default Next waits until the source delivers again
+func WithBufferFailFast() BufferedOption {
+	return func(impl *bufferedGeneratorImpl) error {
+		impl.failFast = true
+		return nil
+	}
+}
+
+// WithBufferWaitTimeout caps how long Next waits for an empty buffer before returning ErrSourceUnavailable.
+// By default Next waits until the source delivers again
+func WithBufferWaitTimeout(timeout time.Duration) BufferedOption {
+	return func(impl *bufferedGeneratorImpl) error {
+		impl.waitTimeout = timeout
+		return nil
+	}
+}
+
+// WithBufferRetryInterval sets the pause between two refills after the source failed, it must be greater than 0.
+// By default 100ms
+func WithBufferRetryInterval(interval time.Duration) BufferedOption {
+	return func(impl *bufferedGeneratorImpl) error {
+		if interval <= 0 {
+			return ErrRetryIntervalInvalid
+		}
+		impl.retryInterval = interval
+		return nil
+	}
+}
+
+// NewBufferedRemoteGenerator returns a Generator handing out IDs of source from a local buffer.
+// The buffer gets refilled up to high in the background as soon as it drops below low
+func NewBufferedRemoteGenerator(source BlockSource, low, high int, options ...BufferedOption) (BufferedGenerator, error) {
+	if low < 0 || high <= 0 || low >= high {
+		return nil, ErrWatermarksInvalid
+	}
+
+	r := &bufferedGeneratorImpl{
+		source:        source,
+		low:           low,
+		high:          high,
+		codec:         Codec{Epoch: time.Unix(0, 0).UTC(), Layout: DefaultLayout},
+		retryInterval: 100 * time.Millisecond,
+		buffer:        make(chan uint64, high),
+		refill:        make(chan struct{}, 1),
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+		unavailable:   make(chan struct{}),
+	}
+
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	go r.run()
+	r.triggerRefill()
+	return r, nil
+}
+
+func (b *bufferedGeneratorImpl) Next() (ID, error) {
+	select {
+	case <-b.done:
+		return nil, ErrGeneratorClosed
+	default:
+	}
+
+	select {
+	case id := <-b.buffer:
+		return b.take(id), nil
+	default:
+	}
+
+	b.triggerRefill()
+
+	b.lock.Lock()
+	unavailable := b.unavailable
+	b.lock.Unlock()
+
+	if !b.failFast {
+		unavailable = nil
+	}
+
+	var timeout <-chan time.Time
+	if b.waitTimeout > 0 {
+		timer := time.NewTimer(b.waitTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case id := <-b.buffer:
+		return b.take(id), nil
+	case <-b.done:
+		return nil, ErrGeneratorClosed
+	case <-unavailable:
+		return nil, b.unavailableErr()
+	case <-timeout:
+		return nil, b.unavailableErr()
+	}
+}
+
+func (b *bufferedGeneratorImpl) MustNext() ID {
+	if r, err := b.Next(); err != nil {
+		panic(err)
+	} else {
+		return r
+	}
+}
+
+// Close stops the background refill, buffered IDs are discarded. It does not wait for a Block call in flight,
+// the IDs it returns are discarded as well
+func (b *bufferedGeneratorImpl) Close() error {
+	b.once.Do(func() {
+		close(b.done)
+	})
+	<-b.stopped
+	return nil
+}
+
+func (b *bufferedGeneratorImpl) take(id uint64) ID {
+	if len(b.buffer) < b.low {
+		b.triggerRefill()
+	}
+	return b.codec.Decode(id)
+}
+
+func (b *bufferedGeneratorImpl) triggerRefill() {
+	select {
+	case b.refill <- struct{}{}:
+	default:
+	}
+}
+
+func (b *bufferedGeneratorImpl) run() {
+	defer close(b.stopped)
+
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-b.refill:
+		}
+
+		for len(b.buffer) < b.high {
+			ids, ok, err := b.block(b.high - len(b.buffer))
+			if !ok {
+				return
+			}
+			if err == nil && len(ids) == 0 {
+				err = ErrEmptyBlock
+			}
+			b.setSourceErr(err)
+
+			if err != nil {
+				select {
+				case <-b.done:
+					return
+				case <-time.After(b.retryInterval):
+				}
+				continue
+			}
+
+			for _, id := range ids {
+				select {
+				case b.buffer <- id:
+				case <-b.done:
+					return
+				}
+			}
+		}
+	}
+}
+
+// block fetches n IDs from the source, it gives up once the generator is closed and leaves the call to finish
+// in the background
+func (b *bufferedGeneratorImpl) block(n int) ([]uint64, bool, error) {
+	type result struct {
+		ids []uint64
+		err error
+	}
+	fetched := make(chan result, 1)
+	go func() {
+		ids, err := b.source.Block(n)
+		fetched <- result{ids, err}
+	}()
+
+	select {
+	case r := <-fetched:
+		return r.ids, true, r.err
+	case <-b.done:
+		return nil, false, nil
+	}
+}
+
+// setSourceErr records the outcome of the last refill and wakes up Next calls waiting in fail fast mode
+func (b *bufferedGeneratorImpl) setSourceErr(err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err != nil && b.sourceErr == nil {
+		close(b.unavailable)
+	}
+	if err == nil && b.sourceErr != nil {
+		b.unavailable = make(chan struct{})
+	}
+	b.sourceErr = err
+}
+
+func (b *bufferedGeneratorImpl) unavailableErr() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.sourceErr == nil {
+		return ErrSourceUnavailable
+	}
+	return fmt.Errorf("%w: %v", ErrSourceUnavailable, b.sourceErr)
+}
@@ -0,0 +1,59 @@
+// Command snowflake-sim runs a simulation scenario and prints its report, see package simulation for the scenario format
+//
+// Usage:
+//
+//	snowflake-sim [scenario-file]
+//
+// The scenario is read from stdin if no file is given. The exit code is 1 if duplicates or ordering violations occurred
+package main
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-snowflake/simulation"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 1 {
+		_, _ = fmt.Fprintln(stderr, "usage: snowflake-sim [scenario-file]")
+		return 2
+	}
+
+	in := stdin
+	if len(args) == 1 {
+		f, err := os.Open(args[0])
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "snowflake-sim: %v\n", err)
+			return 2
+		}
+		defer f.Close()
+		in = f
+	}
+
+	scenario, err := simulation.ParseScenario(in)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "snowflake-sim: %v\n", err)
+		return 2
+	}
+
+	report, err := simulation.Run(scenario)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "snowflake-sim: %v\n", err)
+		return 2
+	}
+
+	_, _ = fmt.Fprint(stdout, report)
+	for _, v := range report.Violations {
+		_, _ = fmt.Fprintf(stdout, "violation:        %s\n", v)
+	}
+
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/has"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("ok from file", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		path := filepath.Join(t.TempDir(), "scenario.txt")
+		assert.That(ioutil.WriteFile(path, []byte("nodes 2\nduration 3\nrate 5\n"), 0600), is.Nil())
+
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		r := run([]string{path}, nil, stdout, stderr)
+		assert.That(r, is.EqualTo(0))
+		assert.That(stdout.String(), has.Prefix("nodes:            2\n"))
+	})
+	t.Run("violations from stdin", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		r := run(nil, strings.NewReader("nodes 1\nduration 3\nrate 2\nat 1 stall node=0 for=1\nat 2 restart node=0"), stdout, stderr)
+		assert.That(r, is.EqualTo(1))
+		assert.That(stdout.String(), has.Suffix("violation:        duplicate id 4503599631564802\n"))
+	})
+	t.Run("invalid scenario", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		r := run(nil, strings.NewReader("nodes 0"), stdout, stderr)
+		assert.That(r, is.EqualTo(2))
+		assert.That(stderr.String(), is.EqualTo("snowflake-sim: nodes must be between 1 and 256, got 0\n"))
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-snowflake"
+	"io"
+)
+
+func runConvert(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("convert", stderr)
+	fromName := fs.String("from", snowflake.EncodingDecimal.String(), "encoding of the input: decimal, hex, base32 or base62")
+	toName := fs.String("to", snowflake.EncodingBase62.String(), "encoding of the output: decimal, hex, base32 or base62")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	from, err := snowflake.ParseEncoding(*fromName)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	to, err := snowflake.ParseEncoding(*toName)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	values, err := inputs(fs.Args(), stdin)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	for _, value := range values {
+		id, err := from.Parse(value)
+		if err != nil {
+			return fail(stderr, fmt.Errorf("%s: %w", value, err))
+		}
+
+		if _, err := fmt.Fprintln(stdout, to.Format(id)); err != nil {
+			return fail(stderr, err)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+func runDecode(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("decode", stderr)
+	flags := &layoutFlags{}
+	flags.register(fs)
+	flags.registerFormat(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	layout, encoding, err := flags.parse()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	values, err := inputs(fs.Args(), stdin)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	for _, value := range values {
+		id, err := encoding.Parse(value)
+		if err != nil {
+			return fail(stderr, fmt.Errorf("%s: %w", value, err))
+		}
+
+		if err := flags.print(stdout, flags.decode(flags.codec(layout).Decode(id), encoding)); err != nil {
+			return fail(stderr, err)
+		}
+	}
+	return 0
+}
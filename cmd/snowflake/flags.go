@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/scarabsoft/go-snowflake"
+	"io"
+	"time"
+)
+
+// layoutFlags are shared by all commands which need to decode IDs
+type layoutFlags struct {
+	epoch    uint64
+	layout   string
+	encoding string
+	format   string
+}
+
+func (l *layoutFlags) register(fs *flag.FlagSet) {
+	fs.Uint64Var(&l.epoch, "epoch", 0, "custom epoch in seconds since the UNIX epoch")
+	fs.StringVar(&l.layout, "layout", snowflake.DefaultLayout.String(), "bits used for timestamp:node:sequence")
+	fs.StringVar(&l.encoding, "encoding", snowflake.EncodingDecimal.String(), "encoding of the IDs: decimal, hex, base32 or base62")
+}
+
+func (l *layoutFlags) registerFormat(fs *flag.FlagSet) {
+	fs.StringVar(&l.format, "format", "text", "output format: text or json")
+}
+
+func (l *layoutFlags) parse() (snowflake.Layout, snowflake.Encoding, error) {
+	layout, err := snowflake.ParseLayout(l.layout)
+	if err != nil {
+		return snowflake.Layout{}, 0, err
+	}
+
+	encoding, err := snowflake.ParseEncoding(l.encoding)
+	if err != nil {
+		return snowflake.Layout{}, 0, err
+	}
+
+	if l.format != "" && l.format != "text" && l.format != "json" {
+		return snowflake.Layout{}, 0, fmt.Errorf("unknown format %q, expected text or json", l.format)
+	}
+	return layout, encoding, nil
+}
+
+// decodedID is the json representation of an ID
+type decodedID struct {
+	ID       string    `json:"id"`
+	Time     time.Time `json:"time"`
+	Seconds  uint64    `json:"seconds"`
+	Node     uint8     `json:"node"`
+	Sequence uint16    `json:"sequence"`
+}
+
+// codec returns the codec of the epoch and layout flags
+func (l *layoutFlags) codec(layout snowflake.Layout) snowflake.Codec {
+	return snowflake.Codec{Epoch: time.Unix(int64(l.epoch), 0), Layout: layout}
+}
+
+func (l *layoutFlags) decode(id snowflake.ExtendedID, encoding snowflake.Encoding) decodedID {
+	return decodedID{
+		ID:       encoding.Format(id.ID()),
+		Time:     id.Time(),
+		Seconds:  id.Seconds(),
+		Node:     id.NodeID(),
+		Sequence: id.Iteration(),
+	}
+}
+
+func (l *layoutFlags) print(w io.Writer, d decodedID) error {
+	if l.format == "json" {
+		return json.NewEncoder(w).Encode(d)
+	}
+	_, err := fmt.Fprintf(w, "%s time=%s node=%d sequence=%d\n", d.ID, d.Time.Format(time.RFC3339), d.Node, d.Sequence)
+	return err
+}
+
+// inputs returns the args or if there are none, all whitespace separated words of stdin
+func inputs(args []string, stdin io.Reader) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+
+	var r []string
+	scanner := bufio.NewScanner(stdin)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		r = append(r, scanner.Text())
+	}
+	return r, scanner.Err()
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func fail(stderr io.Writer, err error) int {
+	_, _ = fmt.Fprintf(stderr, "snowflake: %v\n", err)
+	return 1
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-snowflake"
+	"io"
+)
+
+func runGen(args []string, _ io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("gen", stderr)
+	count := fs.Uint("n", 1, "number of IDs to generate")
+	nodeID := fs.Uint("node", 1, "node id of the generator")
+	flags := &layoutFlags{}
+	flags.register(fs)
+	flags.registerFormat(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	layout, encoding, err := flags.parse()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if *nodeID > uint(layout.MaxNodeID()) {
+		return fail(stderr, fmt.Errorf("node %d does not fit into layout %s", *nodeID, layout))
+	}
+
+	gen, err := snowflake.NewGenerator(
+		snowflake.WithLayout(layout),
+		snowflake.WithNodeID(uint8(*nodeID)),
+		snowflake.WithClock(snowflake.NewUnixClockWithEpoch(flags.epoch)),
+	)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	for i := uint(0); i < *count; i++ {
+		id, err := gen.Next()
+		if err != nil {
+			return fail(stderr, err)
+		}
+
+		if flags.format == "json" {
+			err = flags.print(stdout, flags.decode(flags.codec(layout).Decode(id.ID()), encoding))
+		} else {
+			_, err = fmt.Fprintln(stdout, encoding.Format(id.ID()))
+		}
+		if err != nil {
+			return fail(stderr, err)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+func runHorizon(args []string, _ io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("horizon", stderr)
+	flags := &layoutFlags{}
+	flags.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	layout, _, err := flags.parse()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	_, _ = fmt.Fprintln(stdout, layout.Horizon(flags.epoch).Format(time.RFC3339))
+	return 0
+}
@@ -0,0 +1,58 @@
+// Command snowflake generates, decodes, converts and validates snowflake IDs
+//
+// Usage:
+//
+//	snowflake gen [-n count] [-node id] [-epoch seconds] [-layout t:n:s] [-encoding name] [-format text|json]
+//	snowflake decode [-epoch seconds] [-layout t:n:s] [-encoding name] [-format text|json] [id ...]
+//	snowflake convert -from name -to name [id ...]
+//	snowflake validate [-epoch seconds] [-layout t:n:s] [-encoding name] [id ...]
+//	snowflake horizon [-epoch seconds] [-layout t:n:s]
+//
+// decode, convert and validate read whitespace separated IDs from stdin if no IDs are passed as arguments
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: snowflake <command> [flags] [id ...]
+
+commands:
+  gen        generate new IDs
+  decode     print time, node and sequence of IDs
+  convert    convert IDs between decimal, hex, base32 and base62
+  validate   check that IDs could have been issued by a generator
+  horizon    print the date at which a layout and epoch run out of timestamp bits
+
+run snowflake <command> -h for the flags of a command
+`
+
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"gen":      runGen,
+	"decode":   runDecode,
+	"convert":  runConvert,
+	"validate": runValidate,
+	"horizon":  runHorizon,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	cmd, found := commands[args[0]]
+	if !found {
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	return cmd(args[1:], stdin, stdout, stderr)
+}
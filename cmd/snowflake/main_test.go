@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/has"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"strings"
+	"testing"
+)
+
+func execute(stdin string, args ...string) (int, string, string) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	r := run(args, strings.NewReader(stdin), stdout, stderr)
+	return r, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	t.Run("no command", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, _, stderr := execute("")
+		assert.That(r, is.EqualTo(2))
+		assert.That(stderr, has.Prefix("usage: snowflake"))
+	})
+	t.Run("unknown command", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, _, stderr := execute("", "foo")
+		assert.That(r, is.EqualTo(2))
+		assert.That(stderr, has.Prefix(`unknown command "foo"`))
+	})
+}
+
+func TestGen(t *testing.T) {
+	t.Run("count", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, stdout, _ := execute("", "gen", "-n", "3", "-node", "7")
+		assert.That(r, is.EqualTo(0))
+		assert.That(strings.Fields(stdout), has.Length(3))
+	})
+	t.Run("json", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, stdout, _ := execute("", "gen", "-node", "7", "-format", "json", "-encoding", "hex")
+		assert.That(r, is.EqualTo(0))
+
+		var d decodedID
+		assert.That(json.Unmarshal([]byte(stdout), &d), is.Nil())
+		assert.That(d.Node, is.EqualTo(uint8(7)))
+		assert.That(d.Sequence, is.EqualTo(uint16(1)))
+	})
+	t.Run("node does not fit layout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, _, stderr := execute("", "gen", "-node", "16", "-layout", "44:4:16")
+		assert.That(r, is.EqualTo(1))
+		assert.That(stderr, is.EqualTo("snowflake: node 16 does not fit into layout 44:4:16\n"))
+	})
+	t.Run("invalid layout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, _, _ := execute("", "gen", "-layout", "42:8")
+		assert.That(r, is.EqualTo(1))
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("args", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, stdout, _ := execute("", "decode", "6910615572447233")
+		assert.That(r, is.EqualTo(0))
+		assert.That(stdout, is.EqualTo("6910615572447233 time=2022-03-18T15:59:05Z node=128 sequence=1\n"))
+	})
+	t.Run("stdin", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, stdout, _ := execute("6910615572447233\n6910615572447234\n", "decode")
+		assert.That(r, is.EqualTo(0))
+		assert.That(strings.Split(strings.TrimSpace(stdout), "\n"), has.Length(2))
+	})
+	t.Run("json with epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, stdout, _ := execute("", "decode", "-format", "json", "-epoch", "60", "-encoding", "hex", "188d2b12600001")
+		assert.That(r, is.EqualTo(0))
+		assert.That(stdout, is.EqualTo(`{"id":"188d2b12600001","time":"2022-03-18T16:00:05Z","seconds":1647619145,"node":128,"sequence":1}`+"\n"))
+	})
+	t.Run("invalid id", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, _, stderr := execute("", "decode", "abc")
+		assert.That(r, is.EqualTo(1))
+		assert.That(stderr, is.EqualTo("snowflake: abc: value is not valid in the requested encoding\n"))
+	})
+}
+
+func TestConvert(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	r, stdout, _ := execute("", "convert", "-from", "hex", "-to", "base32", "188d2b12600001")
+	assert.That(r, is.EqualTo(0))
+	assert.That(stdout, is.EqualTo("64d5c960001\n"))
+
+	r, stdout, _ = execute("64d5c960001", "convert", "-from", "base32", "-to", "decimal")
+	assert.That(r, is.EqualTo(0))
+	assert.That(stdout, is.EqualTo("6910615572447233\n"))
+
+	r, _, _ = execute("", "convert", "-to", "base64", "1")
+	assert.That(r, is.EqualTo(1))
+}
+
+func TestValidate(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	r, stdout, _ := execute("", "validate", "6910615572447233")
+	assert.That(r, is.EqualTo(0))
+	assert.That(stdout, is.EqualTo("6910615572447233 valid\n"))
+
+	r, stdout, _ = execute("6910615572447232 99999999999999999 x", "validate")
+	assert.That(r, is.EqualTo(1))
+	assert.That(stdout, is.EqualTo(
+		"6910615572447232 invalid: sequence 0 is never issued\n"+
+			"99999999999999999 invalid: timestamp is in the future\n"+
+			"x invalid: value is not valid in the requested encoding\n",
+	))
+}
+
+func TestHorizon(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	r, stdout, _ := execute("", "horizon", "-layout", "40:8:16", "-epoch", "1288834974")
+	assert.That(r, is.EqualTo(0))
+	assert.That(stdout, is.EqualTo("36852-12-23T02:19:09Z\n"))
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/scarabsoft/go-snowflake"
+	"io"
+	"time"
+)
+
+var (
+	errSequenceZero    = errors.New("sequence 0 is never issued")
+	errTimestampFuture = errors.New("timestamp is in the future")
+)
+
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("validate", stderr)
+	flags := &layoutFlags{}
+	flags.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	layout, encoding, err := flags.parse()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	values, err := inputs(fs.Args(), stdin)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	now := uint64(time.Now().Unix())
+	r := 0
+	for _, value := range values {
+		if err := validate(value, layout, encoding, flags.epoch, now); err != nil {
+			_, _ = fmt.Fprintf(stdout, "%s invalid: %v\n", value, err)
+			r = 1
+		} else {
+			_, _ = fmt.Fprintf(stdout, "%s valid\n", value)
+		}
+	}
+	return r
+}
+
+func validate(value string, layout snowflake.Layout, encoding snowflake.Encoding, epoch, now uint64) error {
+	v, err := encoding.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	id := layout.From(v)
+	if id.Iteration() == 0 {
+		return errSequenceZero
+	}
+	if epoch+id.Seconds() > now {
+		return errTimestampFuture
+	}
+	return nil
+}
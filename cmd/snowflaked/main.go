@@ -0,0 +1,122 @@
+// Command snowflaked serves snowflake IDs over HTTP, see package server for the endpoints
+//
+// Usage:
+//
+//	snowflaked [-addr :8080] [-node id] [-epoch seconds] [-layout t:n:s] [-encoding name] [-max-batch count]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/server"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+type config struct {
+	addr     string
+	nodeID   uint
+	epoch    uint64
+	layout   string
+	encoding string
+	maxBatch int
+}
+
+func main() {
+	cfg, err := parseFlags(os.Args[1:], os.Stderr)
+	if err != nil {
+		os.Exit(2)
+	}
+
+	handler, err := newHandler(cfg)
+	if err != nil {
+		log.Fatalf("snowflaked: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := serve(ctx, cfg.addr, handler); err != nil {
+		log.Fatalf("snowflaked: %v", err)
+	}
+}
+
+func parseFlags(args []string, stderr io.Writer) (config, error) {
+	r := config{}
+	fs := flag.NewFlagSet("snowflaked", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&r.addr, "addr", ":8080", "address to listen on")
+	fs.UintVar(&r.nodeID, "node", 1, "node id of the generator")
+	fs.Uint64Var(&r.epoch, "epoch", 0, "custom epoch in seconds since the UNIX epoch")
+	fs.StringVar(&r.layout, "layout", snowflake.DefaultLayout.String(), "bits used for timestamp:node:sequence")
+	fs.StringVar(&r.encoding, "encoding", snowflake.EncodingDecimal.String(), "encoding of the IDs: decimal, hex, base32 or base62")
+	fs.IntVar(&r.maxBatch, "max-batch", server.DefaultMaxBatch, "max number of IDs per /ids request")
+	return r, fs.Parse(args)
+}
+
+func newHandler(cfg config) (http.Handler, error) {
+	layout, err := snowflake.ParseLayout(cfg.layout)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding, err := snowflake.ParseEncoding(cfg.encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.nodeID > uint(layout.MaxNodeID()) {
+		return nil, fmt.Errorf("node %d does not fit into layout %s", cfg.nodeID, layout)
+	}
+
+	gen, err := snowflake.NewGenerator(
+		snowflake.WithLayout(layout),
+		snowflake.WithNodeID(uint8(cfg.nodeID)),
+		snowflake.WithClock(snowflake.NewUnixClockWithEpoch(cfg.epoch)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return server.New(
+		gen,
+		server.WithLayout(layout),
+		server.WithEpoch(cfg.epoch),
+		server.WithEncoding(encoding),
+		server.WithMaxBatch(cfg.maxBatch),
+	)
+}
+
+// serve runs the http server until ctx is done and then shuts it down gracefully
+func serve(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{Addr: addr, Handler: handler}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewHandler(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		cfg, err := parseFlags([]string{"-node", "7", "-encoding", "hex"}, ioutil.Discard)
+		assert.That(err, is.Nil())
+
+		handler, err := newHandler(cfg)
+		assert.That(err, is.Nil())
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		assert.That(rec.Code, is.EqualTo(http.StatusOK))
+	})
+	t.Run("node does not fit layout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		cfg, err := parseFlags([]string{"-node", "16", "-layout", "44:4:16"}, ioutil.Discard)
+		assert.That(err, is.Nil())
+
+		_, err = newHandler(cfg)
+		assert.That(err.Error(), is.EqualTo("node 16 does not fit into layout 44:4:16"))
+	})
+	t.Run("invalid max batch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		cfg, err := parseFlags([]string{"-max-batch", "0"}, ioutil.Discard)
+		assert.That(err, is.Nil())
+
+		_, err = newHandler(cfg)
+		assert.That(err, is.NotNil())
+	})
+}
@@ -0,0 +1,137 @@
+package snowflake
+
+import (
+	"time"
+)
+
+var (
+	// TwitterEpoch is the epoch of Twitter snowflakes, 2010-11-04T01:42:54.657Z
+	TwitterEpoch = time.Unix(1288834974, 657*int64(time.Millisecond)).UTC()
+	// DiscordEpoch is the epoch of Discord snowflakes, the first second of 2015
+	DiscordEpoch = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	// SonyflakeEpoch is the default start time of Sonyflake, 2014-09-01T00:00:00Z
+	SonyflakeEpoch = time.Date(2014, 9, 1, 0, 0, 0, 0, time.UTC)
+	// InstagramEpoch is the epoch of the Instagram sharded IDs, 2011-08-24T21:07:01.721Z
+	InstagramEpoch = time.Unix(1314220021, 721*int64(time.Millisecond)).UTC()
+)
+
+// Codec decodes and encodes IDs of a given layout and epoch, so the time of an ID can be calculated.
+// Fields name the parts of the node bits, see WithField. SignedSafe reserves the sign bit, see WithSignedSafe
+type Codec struct {
+	Epoch      time.Time
+	Layout     Layout
+	Fields     []Field
+	SignedSafe bool
+}
+
+// NewCodec returns the codec matching a generator created with the same options
+func NewCodec(options ...Option) (Codec, error) {
+	r, err := newGeneratorBuilder(options...)
+	if err != nil {
+		return Codec{}, err
+	}
+	return r.codec(), nil
+}
+
+// Decode returns the ID with the layout and epoch of the codec
+func (c Codec) Decode(id uint64) ExtendedID {
+	return &idImpl{id, c.Layout.internal(), c.epochSeconds(), c.Fields}
+}
+
+// Encode returns the ID generated at t by the given node and sequence, t is truncated to seconds
+func (c Codec) Encode(t time.Time, nodeID uint8, sequence uint16) (ExtendedID, error) {
+	if t.Unix() < int64(c.epochSeconds()) {
+		return nil, ErrTimeBeforeEpoch
+	}
+
+	seconds := uint64(t.Unix()) - c.epochSeconds()
+	if c.SignedSafe && seconds > c.maxTimestamp() && seconds <= c.Layout.internal().MaxTimestamp() {
+		return nil, ErrSignBitReached
+	}
+
+	id, err := c.Layout.internal().Pack(seconds, nodeID, sequence)
+	if err != nil {
+		return nil, err
+	}
+	return c.Decode(id), nil
+}
+
+// MinForTime returns the smallest ID which can be generated within the second of t,
+// e.g. the lower bound of WHERE id BETWEEN ...
+func (c Codec) MinForTime(t time.Time) (ExtendedID, error) {
+	return c.Encode(t, 0, 0)
+}
+
+// MaxForTime returns the largest ID which can be generated within the second of t
+func (c Codec) MaxForTime(t time.Time) (ExtendedID, error) {
+	return c.Encode(t, c.Layout.MaxNodeID(), c.Layout.MaxSequence())
+}
+
+// RangeFor returns the smallest and largest ID which can be generated between the seconds of start and end, both inclusive
+func (c Codec) RangeFor(start, end time.Time) (ExtendedID, ExtendedID, error) {
+	if end.Before(start) {
+		return nil, nil, ErrRangeInvalid
+	}
+
+	min, err := c.MinForTime(start)
+	if err != nil {
+		return nil, nil, err
+	}
+	max, err := c.MaxForTime(end)
+	if err != nil {
+		return nil, nil, err
+	}
+	return min, max, nil
+}
+
+// MinForNode returns the smallest ID which can be generated by nodeID within the second of t
+func (c Codec) MinForNode(t time.Time, nodeID uint8) (ExtendedID, error) {
+	return c.Encode(t, nodeID, 0)
+}
+
+// MaxForNode returns the largest ID which can be generated by nodeID within the second of t
+func (c Codec) MaxForNode(t time.Time, nodeID uint8) (ExtendedID, error) {
+	return c.Encode(t, nodeID, c.Layout.MaxSequence())
+}
+
+// RangeForNode is RangeFor of a single node. IDs of other nodes fall into the range as soon as it spans more than
+// one second, so the node still has to be filtered
+func (c Codec) RangeForNode(start, end time.Time, nodeID uint8) (ExtendedID, ExtendedID, error) {
+	if end.Before(start) {
+		return nil, nil, ErrRangeInvalid
+	}
+
+	min, err := c.MinForNode(start, nodeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	max, err := c.MaxForNode(end, nodeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return min, max, nil
+}
+
+// Horizon returns the date at which the codec runs out of timestamp bits, or reaches the sign bit if SignedSafe
+func (c Codec) Horizon() time.Time {
+	layout := c.Layout.internal()
+	if c.SignedSafe && layout.TimestampBits > 0 {
+		layout.TimestampBits--
+	}
+	return layout.Horizon(c.epochSeconds())
+}
+
+// maxTimestamp returns the largest timestamp the codec accepts
+func (c Codec) maxTimestamp() uint64 {
+	if c.SignedSafe {
+		return c.Layout.internal().MaxTimestamp() >> 1
+	}
+	return c.Layout.internal().MaxTimestamp()
+}
+
+func (c Codec) epochSeconds() uint64 {
+	if c.Epoch.Unix() < 0 {
+		return 0
+	}
+	return uint64(c.Epoch.Unix())
+}
@@ -0,0 +1,87 @@
+package snowflake
+
+import (
+	"sort"
+	"time"
+)
+
+// Compare orders IDs by time, then node, then sequence. It returns -1 if a is before b, 1 if a is after b and 0 otherwise.
+// IDs are compared by their decoded time, so IDs of different layouts and epochs can be mixed
+// and Sort orders them with it. IDs which are no ExtendedID count from the UNIX epoch
+func Compare(a, b ID) int {
+	ta, tb := timeOf(a), timeOf(b)
+	na, nb := nodeOf(a), nodeOf(b)
+	switch {
+	case ta.Before(tb):
+		return -1
+	case ta.After(tb):
+		return 1
+	case na != nb:
+		if na < nb {
+			return -1
+		}
+		return 1
+	case a.Iteration() != b.Iteration():
+		if a.Iteration() < b.Iteration() {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// Before reports whether a orders before b, see Compare
+func Before(a, b ID) bool {
+	return Compare(a, b) < 0
+}
+
+// After reports whether a orders after b, see Compare
+func After(a, b ID) bool {
+	return Compare(a, b) > 0
+}
+
+// Sub returns the time elapsed between the generation of b and a
+func Sub(a, b ID) time.Duration {
+	return timeOf(a).Sub(timeOf(b))
+}
+
+// Since returns the time elapsed since id was generated
+func Since(id ID) time.Duration {
+	return time.Since(timeOf(id))
+}
+
+// timeOf returns the time of an ExtendedID, the seconds since the UNIX epoch for any other ID
+func timeOf(id ID) time.Time {
+	if e, ok := id.(ExtendedID); ok {
+		return e.Time()
+	}
+	return time.Unix(int64(id.Seconds()), 0).UTC()
+}
+
+// nodeOf returns the full width node of an ExtendedID, NodeID for any other ID
+func nodeOf(id ID) uint16 {
+	if e, ok := id.(ExtendedID); ok {
+		return e.Node()
+	}
+	return uint16(id.NodeID())
+}
+
+// ByTime implements sort.Interface ordering IDs like Compare
+type ByTime []ID
+
+func (b ByTime) Len() int {
+	return len(b)
+}
+
+func (b ByTime) Less(i, j int) bool {
+	return Before(b[i], b[j])
+}
+
+func (b ByTime) Swap(i, j int) {
+	b[i], b[j] = b[j], b[i]
+}
+
+// Sort sorts ids in place like Compare, IDs of the same node and second keep the order of their sequence
+func Sort(ids []ID) {
+	sort.Sort(ByTime(ids))
+}
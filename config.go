@@ -0,0 +1,165 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvNodeID              = "NODE_ID"
+	EnvGenesisEpochSeconds = "GENESIS_EPOCH_SECONDS"
+	EnvLayout              = "SNOWFLAKE_LAYOUT"
+	EnvMaxSequence         = "SNOWFLAKE_MAX_SEQUENCE"
+	// EnvConfigFile points to a json or yaml file which gets loaded before the other variables are applied
+	EnvConfigFile = "SNOWFLAKE_CONFIG"
+)
+
+var (
+	ErrConfigInvalid = errors.New("invalid config")
+)
+
+// Config describes a generator in a form which can be loaded from environment variables, json or yaml files
+type Config struct {
+	// NodeID of the generator, must fit into the node bits of the layout. By default 1
+	NodeID int `json:"node_id" yaml:"node_id"`
+	// Epoch in seconds since the UNIX epoch. By default 0
+	Epoch uint64 `json:"epoch" yaml:"epoch"`
+	// Layout in the form timestamp:node:sequence. By default 42:8:14
+	Layout string `json:"layout" yaml:"layout"`
+	// MaxSequence per second, 0 uses the max sequence of the layout. By default 0
+	MaxSequence int `json:"max_sequence" yaml:"max_sequence"`
+}
+
+// DefaultConfig returns the config of a generator created by NewGenerator() without options
+func DefaultConfig() Config {
+	return Config{
+		NodeID: 1,
+		Layout: DefaultLayout.String(),
+	}
+}
+
+// LoadConfigFile loads a config from a json file or a yaml file ending with .yaml or .yml.
+// Missing fields keep their defaults
+func LoadConfigFile(path string) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	r := DefaultConfig()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &r)
+	default:
+		err = json.Unmarshal(data, &r)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %s: %v", ErrConfigInvalid, path, err)
+	}
+
+	return r, r.Validate()
+}
+
+// ConfigFromEnv loads the file referenced by SNOWFLAKE_CONFIG if set and overrides its values with
+// NODE_ID, GENESIS_EPOCH_SECONDS, SNOWFLAKE_LAYOUT and SNOWFLAKE_MAX_SEQUENCE
+func ConfigFromEnv() (Config, error) {
+	r := DefaultConfig()
+
+	if path, found := os.LookupEnv(EnvConfigFile); found {
+		var err error
+		if r, err = LoadConfigFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if v, found := os.LookupEnv(EnvNodeID); found {
+		nodeID, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: %s %q is not a number", ErrConfigInvalid, EnvNodeID, v)
+		}
+		r.NodeID = nodeID
+	}
+
+	if v, found := os.LookupEnv(EnvGenesisEpochSeconds); found {
+		epoch, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: %s %q is not a number of seconds", ErrConfigInvalid, EnvGenesisEpochSeconds, v)
+		}
+		r.Epoch = epoch
+	}
+
+	if v, found := os.LookupEnv(EnvLayout); found {
+		r.Layout = v
+	}
+
+	if v, found := os.LookupEnv(EnvMaxSequence); found {
+		maxSequence, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: %s %q is not a number", ErrConfigInvalid, EnvMaxSequence, v)
+		}
+		r.MaxSequence = maxSequence
+	}
+
+	return r, r.Validate()
+}
+
+// Validate checks that a generator can be created from the config
+func (c Config) Validate() error {
+	_, err := c.Options()
+	return err
+}
+
+// Options returns the generator options described by the config
+func (c Config) Options() ([]Option, error) {
+	layout := DefaultLayout
+	if c.Layout != "" {
+		var err error
+		if layout, err = ParseLayout(c.Layout); err != nil {
+			return nil, fmt.Errorf("%w: layout: %v", ErrConfigInvalid, err)
+		}
+	}
+
+	if c.NodeID < 0 || c.NodeID > int(layout.MaxNodeID()) {
+		return nil, fmt.Errorf("%w: node_id %d is not between 0 and %d of layout %s", ErrConfigInvalid, c.NodeID, layout.MaxNodeID(), layout)
+	}
+
+	if c.MaxSequence < 0 || c.MaxSequence > int(layout.MaxSequence()) {
+		return nil, fmt.Errorf("%w: max_sequence %d is not between 0 and %d of layout %s", ErrConfigInvalid, c.MaxSequence, layout.MaxSequence(), layout)
+	}
+
+	return []Option{
+		WithLayout(layout),
+		WithNodeID(uint8(c.NodeID)),
+		WithClock(NewUnixClockWithEpoch(c.Epoch)),
+		WithMaxSequence(uint16(c.MaxSequence)),
+	}, nil
+}
+
+// Horizon returns the date at which the layout and epoch of the config run out of timestamp bits
+func (c Config) Horizon() (time.Time, error) {
+	layout := DefaultLayout
+	if c.Layout != "" {
+		var err error
+		if layout, err = ParseLayout(c.Layout); err != nil {
+			return time.Time{}, fmt.Errorf("%w: layout: %v", ErrConfigInvalid, err)
+		}
+	}
+	return layout.Horizon(c.Epoch), nil
+}
+
+// NewGeneratorFromConfig validates the config and returns a new generator for it
+func NewGeneratorFromConfig(config Config, options ...Option) (Generator, error) {
+	configOptions, err := config.Options()
+	if err != nil {
+		return nil, err
+	}
+	return NewGenerator(append(configOptions, options...)...)
+}
@@ -0,0 +1,180 @@
+package snowflake
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-snowflake/internal"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSlewRate is the maximal change of the applied offset per elapsed second, 500ppm like common time-sync agents
+	DefaultSlewRate = 0.0005
+	// DefaultOffsetRefresh is how often the offset source is read
+	DefaultOffsetRefresh = time.Second
+)
+
+// uncertaintyPoll is how often a generator waiting for the uncertainty to drop checks the clock again
+const uncertaintyPoll = 10 * time.Millisecond
+
+// OffsetSource provides the estimated offset of the local clock and the bound of its error, e.g. published by a
+// time-sync agent. The offset is added to the local clock
+type OffsetSource interface {
+	Offset() (offset, uncertainty time.Duration, err error)
+}
+
+// CorrectedClock is a Clock corrected by the offset of an OffsetSource
+type CorrectedClock interface {
+	Clock
+	// Uncertainty returns the error bound of the corrected time, the error of the source if it could not be read
+	Uncertainty() (time.Duration, error)
+}
+
+type correctedClockConfig struct {
+	rate    float64
+	refresh time.Duration
+	now     func() time.Time
+}
+
+type CorrectionOption func(*correctedClockConfig) error
+
+// WithSlewRate sets the maximal change of the applied offset per elapsed second. By default DefaultSlewRate
+func WithSlewRate(rate float64) CorrectionOption {
+	return func(c *correctedClockConfig) error {
+		if rate <= 0 {
+			return ErrCorrectionInvalid
+		}
+		c.rate = rate
+		return nil
+	}
+}
+
+// WithOffsetRefresh sets how often the offset source is read. By default DefaultOffsetRefresh
+func WithOffsetRefresh(refresh time.Duration) CorrectionOption {
+	return func(c *correctedClockConfig) error {
+		if refresh < 0 {
+			return ErrCorrectionInvalid
+		}
+		c.refresh = refresh
+		return nil
+	}
+}
+
+// WithCorrectionNow replaces the time used for slewing and refreshing, mainly useful for tests. By default time.Now
+func WithCorrectionNow(now func() time.Time) CorrectionOption {
+	return func(c *correctedClockConfig) error {
+		c.now = now
+		return nil
+	}
+}
+
+// NewCorrectedClock returns a clock adding the offset of source to base. The first offset is applied at once,
+// later changes are slewed so the clock never steps and never moves backwards. The clock keeps the epoch of base.
+// The offset is added before truncating to whole seconds if base has a Now() time.Time method like the unix and
+// coarse clocks, other clocks are taken at the start of their current second
+func NewCorrectedClock(base Clock, source OffsetSource, options ...CorrectionOption) (CorrectedClock, error) {
+	c := &correctedClockConfig{rate: DefaultSlewRate, refresh: DefaultOffsetRefresh, now: time.Now}
+	for _, option := range options {
+		if err := option(c); err != nil {
+			return nil, err
+		}
+	}
+	return internal.NewCorrectedClock(base, source, c.rate, c.refresh, c.now).(CorrectedClock), nil
+}
+
+type fileOffsetSourceImpl struct {
+	path   string
+	maxAge time.Duration
+}
+
+// NewFileOffsetSource returns an OffsetSource reading the file at path, which holds the offset and the uncertainty
+// as durations separated by whitespace, e.g. "-1.5ms 250us". Files not modified within maxAge are rejected with
+// ErrOffsetStale, maxAge 0 accepts files of any age
+func NewFileOffsetSource(path string, maxAge time.Duration) OffsetSource {
+	return &fileOffsetSourceImpl{path: path, maxAge: maxAge}
+}
+
+func (f *fileOffsetSourceImpl) Offset() (time.Duration, time.Duration, error) {
+	if f.maxAge > 0 {
+		info, err := os.Stat(f.path)
+		if err != nil {
+			return 0, 0, err
+		}
+		if age := time.Since(info.ModTime()); age > f.maxAge {
+			return 0, 0, fmt.Errorf("%w: %s was modified %s ago", ErrOffsetStale, f.path, age.Round(time.Second))
+		}
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	parts := strings.Fields(string(data))
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%w: %s", ErrOffsetInvalid, f.path)
+	}
+	offset, err := time.ParseDuration(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %s: %v", ErrOffsetInvalid, f.path, err)
+	}
+	uncertainty, err := time.ParseDuration(parts[1])
+	if err != nil || uncertainty < 0 {
+		return 0, 0, fmt.Errorf("%w: %s: uncertainty %s", ErrOffsetInvalid, f.path, parts[1])
+	}
+	return offset, uncertainty, nil
+}
+
+// WithMaxUncertainty refuses IDs with ErrUncertaintyExceeded while the uncertainty of the clock exceeds threshold.
+// With a wait greater than 0 Next waits up to wait for the uncertainty to drop before refusing. The clock must be
+// a CorrectedClock, otherwise NewGenerator fails with ErrUncertaintyUnavailable
+func WithMaxUncertainty(threshold time.Duration, wait time.Duration) Option {
+	return func(impl *generatorBuilderImpl) error {
+		if threshold < 0 || wait < 0 {
+			return ErrCorrectionInvalid
+		}
+		impl.maxUncertainty = threshold
+		impl.uncertaintyWait = wait
+		impl.uncertaintyGuard = true
+		return nil
+	}
+}
+
+// uncertainGeneratorImpl refuses IDs of the wrapped generator while the clock is too uncertain
+type uncertainGeneratorImpl struct {
+	gen       internal.SnowflakeGenerator
+	clock     CorrectedClock
+	threshold time.Duration
+	wait      time.Duration
+}
+
+func (u *uncertainGeneratorImpl) Next() (uint64, error) {
+	deadline := time.Now().Add(u.wait)
+	for {
+		err := u.check()
+		if err == nil {
+			return u.gen.Next()
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return 0, err
+		}
+		if remaining > uncertaintyPoll {
+			remaining = uncertaintyPoll
+		}
+		time.Sleep(remaining)
+	}
+}
+
+func (u *uncertainGeneratorImpl) check() error {
+	uncertainty, err := u.clock.Uncertainty()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUncertaintyExceeded, err)
+	}
+	if uncertainty > u.threshold {
+		return fmt.Errorf("%w: %s > %s", ErrUncertaintyExceeded, uncertainty, u.threshold)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-snowflake/internal"
+	"strconv"
+)
+
+// Encoding describes a textual representation of an ID
+type Encoding uint8
+
+const (
+	// EncodingDecimal formats IDs as base 10 numbers, the same as ID.String()
+	EncodingDecimal Encoding = iota
+	// EncodingHex formats IDs as lower case base 16 numbers
+	EncodingHex
+	// EncodingBase32 formats IDs using the lower case Crockford alphabet
+	EncodingBase32
+	// EncodingBase62 formats IDs using digits, upper and lower case letters
+	EncodingBase62
+)
+
+var encodingNames = map[Encoding]string{
+	EncodingDecimal: "decimal",
+	EncodingHex:     "hex",
+	EncodingBase32:  "base32",
+	EncodingBase62:  "base62",
+}
+
+// ParseEncoding returns the Encoding for one of decimal, hex, base32 or base62
+func ParseEncoding(name string) (Encoding, error) {
+	for encoding, encodingName := range encodingNames {
+		if encodingName == name {
+			return encoding, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown encoding %q, expected one of decimal, hex, base32, base62", name)
+}
+
+func (e Encoding) String() string {
+	if name, found := encodingNames[e]; found {
+		return name
+	}
+	return fmt.Sprintf("Encoding(%d)", uint8(e))
+}
+
+// Format returns the textual representation of id
+func (e Encoding) Format(id uint64) string {
+	switch e {
+	case EncodingHex:
+		return strconv.FormatUint(id, 16)
+	case EncodingBase32:
+		return internal.FormatBase32(id)
+	case EncodingBase62:
+		return internal.FormatBase62(id)
+	default:
+		return strconv.FormatUint(id, 10)
+	}
+}
+
+// Parse parses the textual representation of an id, returns ErrInvalidEncoding if s is not valid
+func (e Encoding) Parse(s string) (uint64, error) {
+	switch e {
+	case EncodingHex:
+		return parseUint(s, 16)
+	case EncodingBase32:
+		return internal.ParseBase32(s)
+	case EncodingBase62:
+		return internal.ParseBase62(s)
+	default:
+		return parseUint(s, 10)
+	}
+}
+
+func parseUint(s string, base int) (uint64, error) {
+	if r, err := strconv.ParseUint(s, base, 64); err != nil {
+		return 0, ErrInvalidEncoding
+	} else {
+		return r, nil
+	}
+}
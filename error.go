@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-snowflake/internal"
+)
+
+var (
+	ErrClockNotMonotonic     = internal.ErrClockNotMonotonic
+	ErrMaxSequenceOutOfRange = internal.ErrMaxSequenceOutOfRange
+	ErrLayoutInvalid         = internal.ErrLayoutInvalid
+	ErrNodeIDOutOfRange      = internal.ErrNodeIDOutOfRange
+	ErrInvalidEncoding       = internal.ErrInvalidEncoding
+	ErrSequenceOutOfRange    = internal.ErrSequenceOutOfRange
+	ErrTimestampOverflow     = internal.ErrTimestampOverflow
+	ErrEpochInFuture         = internal.ErrEpochInFuture
+	ErrVariantInvalid        = internal.ErrVariantInvalid
+	ErrFieldsInvalid         = internal.ErrFieldsInvalid
+	ErrNodeIDsInvalid        = internal.ErrNodeIDsInvalid
+	ErrFieldValueOutOfRange  = internal.ErrFieldValueOutOfRange
+	ErrReservationUsed       = internal.ErrReservationUsed
+
+	ErrWatermarksInvalid      = errors.New("watermarks must satisfy 0 <= low < high")
+	ErrSourceUnavailable      = errors.New("block source is unavailable")
+	ErrRetryIntervalInvalid   = errors.New("retry interval must be greater than 0")
+	ErrEmptyBlock             = errors.New("block source returned no IDs")
+	ErrGeneratorClosed        = errors.New("generator is closed")
+	ErrEpochBeforeUnixEpoch   = errors.New("epoch must not be before the UNIX epoch")
+	ErrTimeBeforeEpoch        = errors.New("time is before the epoch")
+	ErrSignBitReached         = errors.New("timestamp would set the sign bit of the ID")
+	ErrRangeInvalid           = errors.New("end of the range is before its start")
+	ErrNodeIDInUse            = errors.New("node id is in use by another generator of this process")
+	ErrMonitorInvalid         = errors.New("monitor interval, tolerance and recovery must be greater than 0")
+	ErrClockUntrustworthy     = errors.New("clock is untrustworthy")
+	ErrCorrectionInvalid      = errors.New("slew rate must be greater than 0, refresh, threshold and wait must not be negative")
+	ErrOffsetInvalid          = errors.New("offset source holds no valid offset and uncertainty")
+	ErrOffsetStale            = errors.New("offset source is stale")
+	ErrUncertaintyExceeded    = errors.New("clock uncertainty exceeds the threshold")
+	ErrUncertaintyUnavailable = errors.New("clock does not report its uncertainty")
+	ErrPacingInvalid          = errors.New("paced sequence needs a max sequence and a burst greater than 0, and a single node id")
+	ErrStartupWaitInvalid     = errors.New("max startup wait must be greater than 0")
+	ErrClockBehindMarker      = errors.New("clock did not pass the guarded tick within the max startup wait")
+	ErrHybridOptionInvalid    = errors.New("hybrid logical clock generators can not use a sequence provider, several node ids or a random sequence start")
+	ErrUnitInvalid            = errors.New("unit must be a divisor or a multiple of a second")
+)
@@ -0,0 +1,53 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"testing"
+)
+
+func FuzzGenerator_From(f *testing.F) {
+	f.Add(uint64(1647619145), uint8(128), uint16(1), uint8(8), uint8(13))
+	f.Add(uint64(0), uint8(0), uint16(1), uint8(0), uint8(15))
+
+	f.Fuzz(func(t *testing.T, seconds uint64, nodeID uint8, iteration uint16, nodeBits, sequenceBits uint8) {
+		layout := snowflake.Layout{NodeBits: nodeBits % 9, SequenceBits: sequenceBits%16 + 1}
+		layout.TimestampBits = 64 - layout.NodeBits - layout.SequenceBits
+
+		seconds &= 1<<layout.TimestampBits - 1
+		iteration &= layout.MaxSequence()
+
+		gen, err := snowflake.NewGenerator(
+			snowflake.WithLayout(layout),
+			snowflake.WithNodeID(nodeID),
+			snowflake.WithSequenceProvider(snowflaketest.NewScriptedSequenceProvider(snowflaketest.Ok(seconds, iteration))),
+		)
+		if nodeID > layout.MaxNodeID() {
+			if err != snowflake.ErrNodeIDOutOfRange {
+				t.Fatalf("layout %v accepted node id %d: %v", layout, nodeID, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id, err := gen.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded := layout.From(id.ID())
+		if decoded.Seconds() != seconds || decoded.NodeID() != nodeID || decoded.Iteration() != iteration {
+			t.Fatalf("layout %v decoded %d %d %d, expected %d %d %d",
+				layout, decoded.Seconds(), decoded.NodeID(), decoded.Iteration(), seconds, nodeID, iteration)
+		}
+		if layout == snowflake.DefaultLayout {
+			r := snowflake.From(id.ID())
+			if r.Seconds() != seconds || r.NodeID() != nodeID || r.Iteration() != iteration {
+				t.Fatalf("From decoded %d %d %d, expected %d %d %d",
+					r.Seconds(), r.NodeID(), r.Iteration(), seconds, nodeID, iteration)
+			}
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"testing"
+	"time"
+)
+
+func TestEpochInFuture(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	// epochs are seconds, passing nanoseconds puts the epoch far into the future
+	_, err := snowflake.NewGenerator(
+		snowflake.WithClock(snowflake.NewUnixClockWithEpoch(uint64(time.Now().UnixNano()))),
+	)
+	assert.That(err, is.EqualTo(snowflake.ErrEpochInFuture))
+
+	_, err = snowflake.NewGeneratorFromConfig(snowflake.Config{NodeID: 1, Epoch: uint64(time.Now().Add(time.Hour).Unix())})
+	assert.That(err, is.EqualTo(snowflake.ErrEpochInFuture))
+}
+
+func TestTimestampOverflow(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	clock := snowflaketest.NewManualClock(1<<40 - 1)
+	gen, err := snowflake.NewGenerator(
+		snowflake.WithLayout(snowflake.Layout{TimestampBits: 40, NodeBits: 8, SequenceBits: 16}),
+		snowflake.WithClock(clock),
+	)
+	assert.That(err, is.Nil())
+
+	_, err = gen.Next()
+	assert.That(err, is.Nil())
+
+	clock.Advance(1)
+	_, err = gen.Next()
+	assert.That(err, is.EqualTo(snowflake.ErrTimestampOverflow))
+}
+
+func TestHorizon(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	layout := snowflake.Layout{TimestampBits: 40, NodeBits: 8, SequenceBits: 16}
+	assert.That(layout.Horizon(0).Unix(), is.EqualTo(int64(1<<40-1)))
+
+	r, err := snowflake.Config{Layout: "40:8:16", Epoch: 1288834974}.Horizon()
+	assert.That(err, is.Nil())
+	assert.That(r.Unix(), is.EqualTo(int64(1288834974+1<<40-1)))
+}
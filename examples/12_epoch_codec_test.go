@@ -0,0 +1,106 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"testing"
+	"time"
+)
+
+func TestWithEpoch(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen, err := snowflake.NewGenerator(snowflake.WithEpoch(epoch))
+	assert.That(err, is.Nil())
+
+	before := time.Now().Unix()
+	id := gen.MustNext().(snowflake.ExtendedID)
+	after := time.Now().Unix()
+
+	assert.That(id.Seconds(), is.EqualTo(uint64(id.Time().Unix()-epoch.Unix())))
+	assert.That(id.Time().Unix() >= before, is.True())
+	assert.That(id.Time().Unix() <= after, is.True())
+
+	t.Run("before unix epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(snowflake.WithEpoch(time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)))
+		assert.That(err, is.EqualTo(snowflake.ErrEpochBeforeUnixEpoch))
+	})
+
+	t.Run("in future", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(snowflake.WithEpoch(time.Now().Add(time.Hour)))
+		assert.That(err, is.EqualTo(snowflake.ErrEpochInFuture))
+	})
+}
+
+func TestEpochPresets(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	assert.That(snowflake.TwitterEpoch.UnixNano()/int64(time.Millisecond), is.EqualTo(int64(1288834974657)))
+	assert.That(snowflake.DiscordEpoch.UnixNano()/int64(time.Millisecond), is.EqualTo(int64(1420070400000)))
+	assert.That(snowflake.SonyflakeEpoch.Unix(), is.EqualTo(int64(1409529600)))
+}
+
+func TestCodec(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	options := []snowflake.Option{
+		snowflake.WithEpoch(snowflake.DiscordEpoch),
+		snowflake.WithLayout(snowflake.Layout{TimestampBits: 40, NodeBits: 8, SequenceBits: 16}),
+	}
+
+	codec, err := snowflake.NewCodec(options...)
+	assert.That(err, is.Nil())
+	assert.That(codec.Epoch.Equal(snowflake.DiscordEpoch), is.True())
+	assert.That(codec.Layout, is.EqualTo(snowflake.Layout{TimestampBits: 40, NodeBits: 8, SequenceBits: 16}))
+
+	t.Run("decodes ids of the generator", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		gen, err := snowflake.NewGenerator(options...)
+		assert.That(err, is.Nil())
+
+		id := gen.MustNext().(snowflake.ExtendedID)
+		decoded := codec.Decode(id.ID())
+		assert.That(decoded.Time(), is.EqualTo(id.Time()))
+		assert.That(decoded.NodeID(), is.EqualTo(id.NodeID()))
+		assert.That(decoded.Iteration(), is.EqualTo(id.Iteration()))
+	})
+
+	t.Run("encode", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		at := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+		id, err := codec.Encode(at, 3, 42)
+		assert.That(err, is.Nil())
+		assert.That(id.Time(), is.EqualTo(at))
+		assert.That(id.Seconds(), is.EqualTo(uint64(at.Unix()-snowflake.DiscordEpoch.Unix())))
+		assert.That(id.NodeID(), is.EqualTo(uint8(3)))
+		assert.That(id.Iteration(), is.EqualTo(uint16(42)))
+	})
+
+	t.Run("encode before epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := codec.Encode(snowflake.DiscordEpoch.Add(-time.Second), 1, 1)
+		assert.That(err, is.EqualTo(snowflake.ErrTimeBeforeEpoch))
+	})
+
+	t.Run("horizon", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		assert.That(codec.Horizon().Unix(), is.EqualTo(snowflake.DiscordEpoch.Unix()+1<<40-1))
+	})
+
+	t.Run("layout without epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		id := snowflake.DefaultLayout.From(1 << 22).(snowflake.ExtendedID)
+		assert.That(id.Time(), is.EqualTo(time.Unix(1, 0).UTC()))
+	})
+}
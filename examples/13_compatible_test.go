@@ -0,0 +1,171 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"testing"
+	"time"
+)
+
+func TestCompatibleVectors(t *testing.T) {
+	t.Run("twitter", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		// tweet of the Twitter API docs created at Wed Oct 10 20:19:24 +0000 2018
+		id := snowflake.TwitterVariant.Decode(1050118621198921728)
+		assert.That(id.Time(), is.EqualTo(time.Date(2018, 10, 10, 20, 19, 24, 211*int(time.Millisecond), time.UTC)))
+		assert.That(id.Node(), is.EqualTo(uint16(347)))
+		assert.That(id.Iteration(), is.EqualTo(uint16(0)))
+	})
+
+	t.Run("discord", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		// example of the Discord API docs: worker 1, process 0, increment 7
+		id := snowflake.DiscordVariant.Decode(175928847299117063)
+		assert.That(id.Time(), is.EqualTo(time.Date(2016, 4, 30, 11, 18, 25, 796*int(time.Millisecond), time.UTC)))
+		assert.That(id.Node(), is.EqualTo(uint16(1<<5|0)))
+		assert.That(id.Iteration(), is.EqualTo(uint16(7)))
+	})
+
+	t.Run("instagram", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		// example of the Instagram engineering blog: 1387263000 ms, shard 31341 % 2000, sequence 5001 % 1024
+		id := snowflake.InstagramVariant.Decode(11637205501278089)
+		assert.That(id.Time(), is.EqualTo(snowflake.InstagramEpoch.Add(1387263000*time.Millisecond)))
+		assert.That(id.Node(), is.EqualTo(uint16(1341)))
+		assert.That(id.Iteration(), is.EqualTo(uint16(905)))
+	})
+
+	t.Run("sonyflake", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		// generated by github.com/sony/sonyflake v1.2.0, its Decompose reports
+		// time:38289987437 sequence:2 machine-id:6699
+		id := snowflake.SonyflakeVariant.Decode(642399389867973163)
+		assert.That(id.Time(), is.EqualTo(snowflake.SonyflakeEpoch.Add(38289987437*10*time.Millisecond)))
+		assert.That(id.Seconds(), is.EqualTo(uint64(382899874)))
+		assert.That(id.Node(), is.EqualTo(uint16(6699)))
+		assert.That(id.Iteration(), is.EqualTo(uint16(2)))
+	})
+}
+
+func TestCompatibleGenerators(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 5*int(time.Millisecond), time.UTC)
+
+	for _, tc := range []struct {
+		name    string
+		new     func(uint16, ...snowflake.VariantOption) (snowflake.Generator, error)
+		variant snowflake.Variant
+		node    uint16
+	}{
+		{"twitter", snowflake.NewTwitterCompatible, snowflake.TwitterVariant, 1023},
+		{"discord", snowflake.NewDiscordCompatible, snowflake.DiscordVariant, 1023},
+		{"sonyflake", snowflake.NewSonyflakeCompatible, snowflake.SonyflakeVariant, 65535},
+		{"instagram", snowflake.NewInstagramCompatible, snowflake.InstagramVariant, 8191},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := hamcrest.NewAssertion(t)
+
+			gen, err := tc.new(tc.node, snowflake.WithVariantNow(func() time.Time { return now }))
+			assert.That(err, is.Nil())
+
+			id := gen.MustNext().(snowflake.ExtendedID)
+			assert.That(id.Time(), is.EqualTo(now.Truncate(tc.variant.Unit)))
+			assert.That(id.Node(), is.EqualTo(tc.node))
+			assert.That(id.Iteration(), is.EqualTo(uint16(1)))
+
+			expected, err := tc.variant.Encode(now, tc.node, 1)
+			assert.That(err, is.Nil())
+			assert.That(id.ID(), is.EqualTo(expected.ID()))
+
+			_, err = tc.new(tc.node+1, snowflake.WithVariantNow(func() time.Time { return now }))
+			if tc.node < 65535 {
+				assert.That(err, is.EqualTo(snowflake.ErrNodeIDOutOfRange))
+			}
+		})
+	}
+
+	t.Run("exhausted sequence waits for the next tick", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		gen, err := snowflake.NewSonyflakeCompatible(1)
+		assert.That(err, is.Nil())
+
+		previous := gen.MustNext()
+		for i := 0; i < 1000; i++ {
+			id := gen.MustNext()
+			assert.That(id.ID() > previous.ID(), is.True())
+			previous = id
+		}
+	})
+
+	t.Run("custom epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		epoch := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		gen, err := snowflake.NewInstagramCompatible(1, snowflake.WithVariantEpoch(epoch))
+		assert.That(err, is.Nil())
+		assert.That(gen.MustNext().(snowflake.ExtendedID).Time().After(epoch), is.True())
+
+		_, err = snowflake.NewInstagramCompatible(1, snowflake.WithVariantEpoch(time.Now().Add(time.Hour)))
+		assert.That(err, is.EqualTo(snowflake.ErrEpochInFuture))
+	})
+}
+
+func TestVariant(t *testing.T) {
+	t.Run("validate", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		for _, variant := range []snowflake.Variant{snowflake.TwitterVariant, snowflake.DiscordVariant, snowflake.SonyflakeVariant, snowflake.InstagramVariant} {
+			assert.That(variant.Validate(), is.Nil())
+		}
+
+		invalid := snowflake.TwitterVariant
+		invalid.Unit = 3 * time.Millisecond
+		assert.That(invalid.Validate(), is.EqualTo(snowflake.ErrUnitInvalid))
+
+		invalid = snowflake.TwitterVariant
+		invalid.NodeBits = 20
+		assert.That(invalid.Validate(), is.EqualTo(snowflake.ErrVariantInvalid))
+	})
+
+	t.Run("horizon", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(snowflake.TwitterVariant.Horizon(), is.EqualTo(snowflake.TwitterEpoch.Add((1<<41-1)*time.Millisecond)))
+	})
+
+	t.Run("wide timestamps", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		seconds := snowflake.Variant{Name: "seconds", Epoch: epoch, Unit: time.Second, TimestampBits: 48, NodeBits: 8, SequenceBits: 8}
+		assert.That(seconds.Horizon().Unix(), is.EqualTo(epoch.Unix()+(1<<48-1)))
+
+		at := epoch.AddDate(800, 0, 0)
+		id, err := seconds.Encode(at, 1, 1)
+		assert.That(err, is.Nil())
+		assert.That(id.Time(), is.EqualTo(at))
+
+		millis := snowflake.Variant{Name: "millis", Epoch: epoch, Unit: time.Millisecond, TimestampBits: 50, NodeBits: 6, SequenceBits: 8}
+		assert.That(millis.Horizon(), is.EqualTo(time.Unix(epoch.Unix()+(1<<50-1)/1000, (1<<50-1)%1000*int64(time.Millisecond)).UTC()))
+
+		at = epoch.AddDate(1000, 0, 0).Add(123 * time.Millisecond)
+		id, err = millis.Encode(at, 1, 1)
+		assert.That(err, is.Nil())
+		assert.That(id.Time(), is.EqualTo(at))
+	})
+
+	t.Run("encode before epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := snowflake.DiscordVariant.Encode(snowflake.DiscordEpoch.Add(-time.Millisecond), 1, 1)
+		assert.That(err, is.EqualTo(snowflake.ErrTimeBeforeEpoch))
+	})
+
+	t.Run("string", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(snowflake.SonyflakeVariant.String(), is.EqualTo("sonyflake 39:16:8"))
+	})
+}
@@ -0,0 +1,95 @@
+package examples
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	options := []snowflake.Option{
+		snowflake.WithField("region", 3, 5),
+		snowflake.WithField("worker", 5, 17),
+	}
+
+	gen, err := snowflake.NewGenerator(options...)
+	assert.That(err, is.Nil())
+
+	id := gen.MustNext().(snowflake.ExtendedID)
+	assert.That(id.NodeID(), is.EqualTo(uint8(5<<5|17)))
+
+	region, ok := id.Field("region")
+	assert.That(ok, is.True())
+	assert.That(region, is.EqualTo(uint8(5)))
+
+	worker, ok := id.Field("worker")
+	assert.That(ok, is.True())
+	assert.That(worker, is.EqualTo(uint8(17)))
+
+	_, ok = id.Field("datacenter")
+	assert.That(ok, is.False())
+
+	t.Run("codec", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		codec, err := snowflake.NewCodec(options...)
+		assert.That(err, is.Nil())
+
+		region, ok := codec.Decode(id.ID()).Field("region")
+		assert.That(ok, is.True())
+		assert.That(region, is.EqualTo(uint8(5)))
+
+		_, ok = snowflake.From(id.ID()).(snowflake.ExtendedID).Field("region")
+		assert.That(ok, is.False())
+	})
+
+	t.Run("type tag", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		gen, err := snowflake.NewGenerator(
+			snowflake.WithLayout(snowflake.Layout{TimestampBits: 40, NodeBits: 8, SequenceBits: 16}),
+			snowflake.WithField("type", 2, 3),
+			snowflake.WithField("node", 6, 1),
+		)
+		assert.That(err, is.Nil())
+
+		tag, ok := gen.MustNext().(snowflake.ExtendedID).Field("type")
+		assert.That(ok, is.True())
+		assert.That(tag, is.EqualTo(uint8(3)))
+	})
+
+	t.Run("replaces node id", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		gen, err := snowflake.NewGenerator(append(options, snowflake.WithNodeID(1))...)
+		assert.That(err, is.Nil())
+		assert.That(gen.MustNext().NodeID(), is.EqualTo(uint8(5<<5|17)))
+	})
+
+	t.Run("value out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(snowflake.WithField("region", 3, 8), snowflake.WithField("worker", 5, 0))
+		assert.That(errors.Is(err, snowflake.ErrFieldValueOutOfRange), is.True())
+		assert.That(err.Error(), is.EqualTo("field value does not fit into the bits of the field: region=8 exceeds 3 bits"))
+	})
+
+	t.Run("fields do not fill node bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(snowflake.WithField("region", 3, 1), snowflake.WithField("worker", 4, 1))
+		assert.That(errors.Is(err, snowflake.ErrFieldsInvalid), is.True())
+		assert.That(err.Error(), is.EqualTo("fields must have unique non empty names and use exactly the node bits of the layout: region:3,worker:4 of 8 node bits"))
+	})
+
+	t.Run("duplicated name", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(snowflake.WithField("worker", 3, 1), snowflake.WithField("worker", 5, 1))
+		assert.That(errors.Is(err, snowflake.ErrFieldsInvalid), is.True())
+	})
+}
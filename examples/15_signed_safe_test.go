@@ -0,0 +1,70 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"testing"
+	"time"
+)
+
+func TestSignedSafe(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	clock := snowflaketest.NewManualClock(1<<41 - 1)
+	gen, err := snowflake.NewGenerator(snowflake.WithClock(clock), snowflake.WithSignedSafe())
+	assert.That(err, is.Nil())
+
+	id, err := gen.Next()
+	assert.That(err, is.Nil())
+	signed := id.(snowflake.ExtendedID).Int64()
+	assert.That(signed > 0, is.True())
+	assert.That(uint64(signed), is.EqualTo(id.ID()))
+
+	clock.Advance(1)
+	_, err = gen.Next()
+	assert.That(err, is.EqualTo(snowflake.ErrSignBitReached))
+
+	t.Run("without signed safe", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		gen, err := snowflake.NewGenerator(snowflake.WithClock(snowflaketest.NewManualClock(1 << 41)))
+		assert.That(err, is.Nil())
+
+		id, err := gen.Next()
+		assert.That(err, is.Nil())
+		assert.That(id.(snowflake.ExtendedID).Int64() < 0, is.True())
+	})
+
+	t.Run("horizon already crossed", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(
+			snowflake.WithLayout(snowflake.Layout{TimestampBits: 40, NodeBits: 8, SequenceBits: 16}),
+			snowflake.WithClock(snowflaketest.NewManualClock(1<<39)),
+			snowflake.WithSignedSafe(),
+		)
+		assert.That(err, is.EqualTo(snowflake.ErrSignBitReached))
+	})
+
+	t.Run("codec", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		codec, err := snowflake.NewCodec(snowflake.WithSignedSafe())
+		assert.That(err, is.Nil())
+		assert.That(codec.SignedSafe, is.True())
+		assert.That(codec.Horizon(), is.EqualTo(time.Unix(1<<41-1, 0).UTC()))
+
+		_, err = codec.Encode(time.Unix(1<<41-1, 0), 1, 1)
+		assert.That(err, is.Nil())
+
+		_, err = codec.Encode(time.Unix(1<<41, 0), 1, 1)
+		assert.That(err, is.EqualTo(snowflake.ErrSignBitReached))
+
+		codec.SignedSafe = false
+		id, err := codec.Encode(time.Unix(1<<41, 0), 1, 1)
+		assert.That(err, is.Nil())
+		assert.That(id.Int64() < 0, is.True())
+	})
+}
@@ -0,0 +1,102 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"testing"
+	"time"
+)
+
+func TestRangeFor(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	codec, err := snowflake.NewCodec(snowflake.WithEpoch(epoch))
+	assert.That(err, is.Nil())
+
+	start := epoch.Add(10 * time.Second)
+	end := epoch.Add(20 * time.Second)
+
+	min, max, err := codec.RangeFor(start, end)
+	assert.That(err, is.Nil())
+	assert.That(min.ID(), is.EqualTo(uint64(10<<22)))
+	assert.That(max.ID(), is.EqualTo(uint64(21<<22-1)))
+
+	t.Run("contains generated ids", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflaketest.NewManualClock(uint64(start.Unix()))
+		gen, err := snowflake.NewGenerator(
+			snowflake.WithClock(clock),
+			snowflake.WithNodeID(255),
+		)
+		assert.That(err, is.Nil())
+
+		codec, err := snowflake.NewCodec(snowflake.WithEpoch(time.Unix(0, 0)))
+		assert.That(err, is.Nil())
+
+		min, max, err := codec.RangeFor(start, end)
+		assert.That(err, is.Nil())
+
+		for _, at := range []time.Time{start, end} {
+			clock.Set(uint64(at.Unix()))
+			id := gen.MustNext()
+			assert.That(id.ID() >= min.ID() && id.ID() <= max.ID(), is.True())
+		}
+
+		clock.Set(uint64(end.Unix()) + 1)
+		assert.That(gen.MustNext().ID() > max.ID(), is.True())
+	})
+
+	t.Run("single second", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		min, err := codec.MinForTime(start.Add(500 * time.Millisecond))
+		assert.That(err, is.Nil())
+		max, err := codec.MaxForTime(start)
+		assert.That(err, is.Nil())
+
+		assert.That(min.Time(), is.EqualTo(start))
+		assert.That(max.Time(), is.EqualTo(start))
+		assert.That(max.NodeID(), is.EqualTo(uint8(255)))
+		assert.That(max.Iteration(), is.EqualTo(uint16(16383)))
+	})
+
+	t.Run("per node", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		min, max, err := codec.RangeForNode(start, end, 7)
+		assert.That(err, is.Nil())
+		assert.That(min.ID(), is.EqualTo(uint64(10<<22|7<<14)))
+		assert.That(max.ID(), is.EqualTo(uint64(20<<22|7<<14|16383)))
+	})
+
+	t.Run("end before start", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, _, err := codec.RangeFor(end, start)
+		assert.That(err, is.EqualTo(snowflake.ErrRangeInvalid))
+
+		_, _, err = codec.RangeForNode(end, start, 1)
+		assert.That(err, is.EqualTo(snowflake.ErrRangeInvalid))
+	})
+
+	t.Run("before epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, _, err := codec.RangeFor(epoch.Add(-time.Second), end)
+		assert.That(err, is.EqualTo(snowflake.ErrTimeBeforeEpoch))
+	})
+
+	t.Run("signed safe", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		codec, err := snowflake.NewCodec(snowflake.WithSignedSafe())
+		assert.That(err, is.Nil())
+
+		_, err = codec.MaxForTime(time.Unix(1<<41, 0))
+		assert.That(err, is.EqualTo(snowflake.ErrSignBitReached))
+	})
+}
@@ -0,0 +1,90 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"sort"
+	"testing"
+	"time"
+)
+
+type plainID interface {
+	snowflake.ID
+}
+
+// foreignID implements only ID, like IDs of other packages
+type foreignID struct {
+	plainID
+}
+
+func TestCompare(t *testing.T) {
+	layout := snowflake.Layout{TimestampBits: 40, NodeBits: 8, SequenceBits: 16}
+	encode := func(seconds uint64, nodeID uint8, sequence uint16) snowflake.ID {
+		id, err := layout.Encode(seconds, nodeID, sequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	t.Run("time node sequence", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		assert.That(snowflake.Compare(encode(1, 9, 9), encode(2, 0, 0)), is.EqualTo(-1))
+		assert.That(snowflake.Compare(encode(2, 0, 0), encode(1, 9, 9)), is.EqualTo(1))
+		assert.That(snowflake.Compare(encode(1, 1, 9), encode(1, 2, 0)), is.EqualTo(-1))
+		assert.That(snowflake.Compare(encode(1, 1, 1), encode(1, 1, 2)), is.EqualTo(-1))
+		assert.That(snowflake.Compare(encode(1, 1, 2), encode(1, 1, 1)), is.EqualTo(1))
+		assert.That(snowflake.Compare(encode(1, 1, 1), encode(1, 1, 1)), is.EqualTo(0))
+
+		assert.That(snowflake.Before(encode(1, 1, 1), encode(1, 1, 2)), is.True())
+		assert.That(snowflake.After(encode(1, 1, 1), encode(1, 1, 2)), is.False())
+		assert.That(snowflake.After(encode(3, 0, 0), encode(1, 1, 2)), is.True())
+	})
+
+	t.Run("different epochs", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		early := snowflake.Codec{Epoch: time.Unix(1000, 0), Layout: snowflake.DefaultLayout}.Decode(10 << 22)
+		late := snowflake.Codec{Epoch: time.Unix(0, 0), Layout: snowflake.DefaultLayout}.Decode(100 << 22)
+		assert.That(snowflake.Before(late, early), is.True())
+		assert.That(snowflake.Sub(early, late), is.EqualTo(910*time.Second))
+	})
+
+	t.Run("sort", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		ids := []snowflake.ID{encode(2, 0, 1), encode(1, 2, 1), encode(1, 1, 2), encode(1, 1, 1), encode(3, 0, 1)}
+		snowflake.Sort(ids)
+
+		expected := []snowflake.ID{encode(1, 1, 1), encode(1, 1, 2), encode(1, 2, 1), encode(2, 0, 1), encode(3, 0, 1)}
+		for i := range expected {
+			assert.That(ids[i].ID(), is.EqualTo(expected[i].ID()))
+		}
+		assert.That(sort.IsSorted(snowflake.ByTime(ids)), is.True())
+	})
+
+	t.Run("sub", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		assert.That(snowflake.Sub(encode(70, 1, 1), encode(10, 2, 2)), is.EqualTo(time.Minute))
+		assert.That(snowflake.Sub(encode(10, 1, 1), encode(70, 2, 2)), is.EqualTo(-time.Minute))
+	})
+
+	t.Run("foreign ID", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		foreign := foreignID{encode(5, 1, 1)}
+		assert.That(snowflake.Compare(foreign, encode(5, 1, 2)), is.EqualTo(-1))
+		assert.That(snowflake.Sub(encode(65, 1, 1), foreign), is.EqualTo(time.Minute))
+	})
+
+	t.Run("since", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		id := snowflake.MustNewGenerator().MustNext()
+		assert.That(snowflake.Since(id) >= 0, is.True())
+		assert.That(snowflake.Since(id) < 2*time.Second, is.True())
+	})
+}
@@ -0,0 +1,140 @@
+package examples
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStartupGuard(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	checker := snowflaketest.NewUniquenessChecker()
+	for restart := 0; restart < 2; restart++ {
+		gen, err := snowflake.NewGenerator(snowflake.WithNodeID(7), snowflake.WithStartupGuard())
+		assert.That(err, is.Nil())
+
+		attached := checker.Attach(gen)
+		for i := 0; i < 100; i++ {
+			attached.MustNext()
+		}
+	}
+	assert.That(len(checker.Violations()), is.EqualTo(0))
+	checker.Check(t)
+}
+
+func TestTickMarker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tick")
+
+	t.Run("waits for persisted tick", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		// the clock is already past the persisted tick 99, so the first generator starts right away and persists 100,
+		// a generator created afterwards has to wait until the clock passes 100
+		assert.That(snowflake.NewFileTickMarker(path).SetLastTick(99), is.Nil())
+
+		clock := snowflaketest.NewManualClock(100)
+		first, err := snowflake.NewGenerator(snowflake.WithClock(clock), snowflake.WithTickMarker(snowflake.NewFileTickMarker(path)))
+		assert.That(err, is.Nil())
+		last := first.MustNext()
+
+		data, err := os.ReadFile(path)
+		assert.That(err, is.Nil())
+		assert.That(string(data), is.EqualTo("100"))
+
+		created := make(chan snowflake.Generator)
+		go func() {
+			gen, _ := snowflake.NewGenerator(snowflake.WithClock(clock), snowflake.WithTickMarker(snowflake.NewFileTickMarker(path)))
+			created <- gen
+		}()
+
+		select {
+		case <-created:
+			t.Fatal("generator was created within the persisted tick")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		clock.Advance(1)
+		second := <-created
+		id := second.MustNext()
+		assert.That(id.Seconds(), is.EqualTo(uint64(101)))
+		assert.That(snowflake.After(id, last), is.True())
+	})
+
+	t.Run("skips wait for earlier tick", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		marker := snowflake.NewFileTickMarker(path)
+		assert.That(marker.SetLastTick(50), is.Nil())
+
+		created := make(chan error)
+		go func() {
+			_, err := snowflake.NewGenerator(snowflake.WithClock(snowflaketest.NewManualClock(100)), snowflake.WithTickMarker(marker))
+			created <- err
+		}()
+
+		select {
+		case err := <-created:
+			assert.That(err, is.Nil())
+		case <-time.After(time.Second):
+			t.Fatal("generator waited although the persisted tick passed")
+		}
+	})
+
+	t.Run("marker ahead of clock", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		marker := snowflake.NewFileTickMarker(path)
+		assert.That(marker.SetLastTick(1000), is.Nil())
+
+		_, err := snowflake.NewGenerator(
+			snowflake.WithClock(snowflaketest.NewManualClock(100)),
+			snowflake.WithTickMarker(marker),
+			snowflake.WithMaxStartupWait(30*time.Millisecond),
+		)
+		assert.That(errors.Is(err, snowflake.ErrClockBehindMarker), is.True())
+
+		_, err = snowflake.NewGenerator(snowflake.WithMaxStartupWait(0))
+		assert.That(err, is.EqualTo(snowflake.ErrStartupWaitInvalid))
+	})
+
+	t.Run("corrupt marker", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		assert.That(os.WriteFile(path, []byte("garbage"), 0o600), is.Nil())
+		_, err := snowflake.NewGenerator(snowflake.WithTickMarker(snowflake.NewFileTickMarker(path)))
+		assert.That(err, is.NotNil())
+	})
+
+	t.Run("marker failure", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		errBroken := errors.New("broken")
+		gen, err := snowflake.NewGenerator(
+			snowflake.WithClock(snowflaketest.NewManualClock(100)),
+			snowflake.WithTickMarker(brokenTickMarker{errBroken}),
+		)
+		assert.That(err, is.Nil())
+
+		_, err = gen.Next()
+		assert.That(err, is.EqualTo(errBroken))
+	})
+}
+
+type brokenTickMarker struct {
+	err error
+}
+
+func (b brokenTickMarker) LastTick() (uint64, bool, error) {
+	return 0, true, nil
+}
+
+func (b brokenTickMarker) SetLastTick(uint64) error {
+	return b.err
+}
@@ -0,0 +1,129 @@
+package examples
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNodeRegistry(t *testing.T) {
+	t.Run("exclusive", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		first, err := snowflake.NewGenerator(snowflake.WithNodeID(42), snowflake.WithNodeRegistry(snowflake.RegistryExclusive))
+		assert.That(err, is.Nil())
+
+		_, err = snowflake.NewGenerator(snowflake.WithNodeID(42), snowflake.WithNodeRegistry(snowflake.RegistryExclusive))
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDInUse))
+
+		assert.That(first.(io.Closer).Close(), is.Nil())
+		_, err = first.Next()
+		assert.That(err, is.EqualTo(snowflake.ErrGeneratorClosed))
+
+		second, err := snowflake.NewGenerator(snowflake.WithNodeID(42), snowflake.WithNodeRegistry(snowflake.RegistryExclusive))
+		assert.That(err, is.Nil())
+		assert.That(second.(io.Closer).Close(), is.Nil())
+	})
+
+	t.Run("other node, epoch or layout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		registered := snowflake.WithNodeRegistry(snowflake.RegistryExclusive)
+		var generators []snowflake.Generator
+		for _, options := range [][]snowflake.Option{
+			{snowflake.WithNodeID(43), registered},
+			{snowflake.WithNodeID(44), registered},
+			{snowflake.WithNodeID(43), snowflake.WithEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), registered},
+			{snowflake.WithNodeID(43), snowflake.WithLayout(snowflake.Layout{TimestampBits: 40, NodeBits: 8, SequenceBits: 16}), registered},
+		} {
+			gen, err := snowflake.NewGenerator(options...)
+			assert.That(err, is.Nil())
+			generators = append(generators, gen)
+		}
+
+		for _, gen := range generators {
+			assert.That(gen.(io.Closer).Close(), is.Nil())
+		}
+	})
+
+	t.Run("shared", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		first, err := snowflake.NewGenerator(snowflake.WithNodeID(45), snowflake.WithNodeRegistry(snowflake.RegistryShared))
+		assert.That(err, is.Nil())
+		second, err := snowflake.NewGenerator(snowflake.WithNodeID(45), snowflake.WithNodeRegistry(snowflake.RegistryShared))
+		assert.That(err, is.Nil())
+
+		a := first.MustNext()
+		b := second.MustNext()
+		assert.That(snowflake.Before(a, b), is.True())
+
+		assert.That(first.(io.Closer).Close(), is.Nil())
+		assert.That(first.(io.Closer).Close(), is.Nil())
+		_, err = snowflake.NewGenerator(snowflake.WithNodeID(45), snowflake.WithNodeRegistry(snowflake.RegistryExclusive))
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDInUse))
+
+		assert.That(second.MustNext().ID() > b.ID(), is.True())
+		assert.That(second.(io.Closer).Close(), is.Nil())
+
+		third, err := snowflake.NewGenerator(snowflake.WithNodeID(45), snowflake.WithNodeRegistry(snowflake.RegistryExclusive))
+		assert.That(err, is.Nil())
+		assert.That(third.(io.Closer).Close(), is.Nil())
+	})
+
+	t.Run("failed generator is not registered", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(
+			snowflake.WithNodeID(46),
+			snowflake.WithMaxSequence(1<<15),
+			snowflake.WithNodeRegistry(snowflake.RegistryExclusive),
+		)
+		assert.That(err, is.EqualTo(snowflake.ErrMaxSequenceOutOfRange))
+
+		gen, err := snowflake.NewGenerator(snowflake.WithNodeID(46), snowflake.WithNodeRegistry(snowflake.RegistryExclusive))
+		assert.That(err, is.Nil())
+		assert.That(gen.(io.Closer).Close(), is.Nil())
+	})
+
+	t.Run("build does not block the registry", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		marker := snowflake.NewFileTickMarker(filepath.Join(t.TempDir(), "tick"))
+		assert.That(marker.SetLastTick(1000), is.Nil())
+
+		failed := make(chan error)
+		go func() {
+			_, err := snowflake.NewGenerator(
+				snowflake.WithNodeID(50),
+				snowflake.WithClock(snowflaketest.NewManualClock(100)),
+				snowflake.WithTickMarker(marker),
+				snowflake.WithMaxStartupWait(200*time.Millisecond),
+				snowflake.WithNodeRegistry(snowflake.RegistryExclusive),
+			)
+			failed <- err
+		}()
+		time.Sleep(20 * time.Millisecond)
+
+		start := time.Now()
+		other, err := snowflake.NewGenerator(snowflake.WithNodeID(51), snowflake.WithNodeRegistry(snowflake.RegistryExclusive))
+		assert.That(err, is.Nil())
+		assert.That(time.Since(start) < 100*time.Millisecond, is.True())
+		assert.That(other.(io.Closer).Close(), is.Nil())
+
+		_, err = snowflake.NewGenerator(snowflake.WithNodeID(50), snowflake.WithNodeRegistry(snowflake.RegistryExclusive))
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDInUse))
+
+		// the failed build releases its reservation
+		assert.That(errors.Is(<-failed, snowflake.ErrClockBehindMarker), is.True())
+		gen, err := snowflake.NewGenerator(snowflake.WithNodeID(50), snowflake.WithNodeRegistry(snowflake.RegistryExclusive))
+		assert.That(err, is.Nil())
+		assert.That(gen.(io.Closer).Close(), is.Nil())
+	})
+}
@@ -0,0 +1,94 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestNodeIDs(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	clock := snowflaketest.NewManualClock(100)
+	gen, err := snowflake.NewGenerator(
+		snowflake.WithClock(clock),
+		snowflake.WithMaxSequence(2),
+		snowflake.WithNodeIDs(1, 2, 3),
+	)
+	assert.That(err, is.Nil())
+
+	checker := snowflaketest.NewUniquenessChecker()
+	gen = checker.Attach(gen)
+
+	var nodes []uint8
+	for i := 0; i < 6; i++ {
+		nodes = append(nodes, gen.MustNext().NodeID())
+	}
+	assert.That(nodes, is.EqualTo([]uint8{1, 1, 2, 2, 3, 3}))
+
+	clock.Advance(1)
+	id := gen.MustNext()
+	assert.That(id.NodeID(), is.EqualTo(uint8(1)))
+	assert.That(id.Iteration(), is.EqualTo(uint16(1)))
+	checker.Check(t)
+
+	t.Run("invalid", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(snowflake.WithNodeIDs())
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDsInvalid))
+
+		_, err = snowflake.NewGenerator(snowflake.WithNodeIDs(1, 1))
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDsInvalid))
+
+		_, err = snowflake.NewGenerator(
+			snowflake.WithLayout(snowflake.Layout{TimestampBits: 44, NodeBits: 4, SequenceBits: 16}),
+			snowflake.WithNodeIDs(15, 16),
+		)
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDOutOfRange))
+	})
+
+	t.Run("registry", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		gen, err := snowflake.NewGenerator(snowflake.WithNodeIDs(50, 51), snowflake.WithNodeRegistry(snowflake.RegistryShared))
+		assert.That(err, is.Nil())
+
+		_, err = snowflake.NewGenerator(snowflake.WithNodeID(51), snowflake.WithNodeRegistry(snowflake.RegistryShared))
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDInUse))
+
+		shared, err := snowflake.NewGenerator(snowflake.WithNodeIDs(50, 51), snowflake.WithNodeRegistry(snowflake.RegistryShared))
+		assert.That(err, is.Nil())
+
+		assert.That(gen.(io.Closer).Close(), is.Nil())
+		assert.That(shared.(io.Closer).Close(), is.Nil())
+
+		other, err := snowflake.NewGenerator(snowflake.WithNodeID(51), snowflake.WithNodeRegistry(snowflake.RegistryExclusive))
+		assert.That(err, is.Nil())
+		assert.That(other.(io.Closer).Close(), is.Nil())
+	})
+
+	t.Run("tick marker", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		marker := snowflake.NewFileTickMarker(filepath.Join(t.TempDir(), "tick"))
+		assert.That(marker.SetLastTick(99), is.Nil())
+
+		gen, err := snowflake.NewGenerator(
+			snowflake.WithClock(snowflaketest.NewManualClock(100)),
+			snowflake.WithNodeIDs(1, 2),
+			snowflake.WithTickMarker(marker),
+		)
+		assert.That(err, is.Nil())
+		gen.MustNext()
+
+		tick, ok, err := marker.LastTick()
+		assert.That(err, is.Nil())
+		assert.That(ok, is.True())
+		assert.That(tick, is.EqualTo(uint64(100)))
+	})
+}
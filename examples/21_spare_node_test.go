@@ -0,0 +1,71 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"testing"
+)
+
+func TestSpareNodeIDs(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	clock := snowflaketest.NewManualClock(100)
+	gen, err := snowflake.NewGenerator(
+		snowflake.WithClock(clock),
+		snowflake.WithNodeID(1),
+		snowflake.WithSpareNodeIDs(200, 201),
+	)
+	assert.That(err, is.Nil())
+
+	checker := snowflaketest.NewUniquenessChecker()
+	gen = checker.Attach(gen)
+
+	assert.That(gen.MustNext().NodeID(), is.EqualTo(uint8(1)))
+
+	// the clock is behind, the first spare takes over until the clock caught up
+	clock.Rewind(5)
+	for i := 0; i < 5; i++ {
+		assert.That(gen.MustNext().NodeID(), is.EqualTo(uint8(200)))
+		clock.Advance(1)
+	}
+	assert.That(gen.MustNext().NodeID(), is.EqualTo(uint8(1)))
+
+	// the first spare is behind as well, the second one takes over
+	clock.Rewind(3)
+	assert.That(gen.MustNext().NodeID(), is.EqualTo(uint8(201)))
+
+	// all nodes are behind
+	clock.Set(90)
+	_, err = gen.Next()
+	assert.That(err, is.EqualTo(snowflake.ErrClockNotMonotonic))
+
+	clock.Set(101)
+	assert.That(gen.MustNext().NodeID(), is.EqualTo(uint8(1)))
+
+	// the checker reports IDs out of order during the rollback, but no duplicates
+	for _, violation := range checker.Violations() {
+		assert.That(violation.Kind, is.EqualTo(snowflaketest.OutOfOrder))
+	}
+
+	t.Run("without spare", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflaketest.NewManualClock(100)
+		gen, err := snowflake.NewGenerator(snowflake.WithClock(clock))
+		assert.That(err, is.Nil())
+		gen.MustNext()
+
+		clock.Rewind(1)
+		_, err = gen.Next()
+		assert.That(err, is.EqualTo(snowflake.ErrClockNotMonotonic))
+	})
+
+	t.Run("spare overlaps node", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(snowflake.WithNodeID(1), snowflake.WithSpareNodeIDs(1))
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDsInvalid))
+	})
+}
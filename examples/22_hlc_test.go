@@ -0,0 +1,149 @@
+package examples
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHLCGenerator(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	// node 2 is 50s behind node 1
+	clock1 := snowflaketest.NewManualClock(100)
+	clock2 := snowflaketest.NewManualClock(50)
+
+	gen1, err := snowflake.NewHLCGenerator(snowflake.WithClock(clock1), snowflake.WithNodeID(1))
+	assert.That(err, is.Nil())
+	gen2, err := snowflake.NewHLCGenerator(snowflake.WithClock(clock2), snowflake.WithNodeID(2))
+	assert.That(err, is.Nil())
+
+	// a message travels 1 -> 2 -> 1 -> 2, every reply must be greater than the message it answers
+	message := gen1.MustNext()
+	for i := 0; i < 3; i++ {
+		receiver := gen2
+		if i%2 == 1 {
+			receiver = gen1
+		}
+
+		receiver.Observe(message)
+		reply := receiver.MustNext()
+		assert.That(reply.ID() > message.ID(), is.True())
+		message = reply
+	}
+
+	t.Run("higher remote node in same tick", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		remote, err := snowflake.DefaultLayout.Encode(100, 2, 5)
+		assert.That(err, is.Nil())
+
+		gen, err := snowflake.NewHLCGenerator(snowflake.WithClock(snowflaketest.NewManualClock(100)), snowflake.WithNodeID(1))
+		assert.That(err, is.Nil())
+
+		gen.Observe(remote)
+		id := gen.MustNext()
+		assert.That(id.ID() > remote.ID(), is.True())
+		assert.That(id.Seconds(), is.EqualTo(uint64(101)))
+	})
+
+	t.Run("clock rollback", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflaketest.NewManualClock(100)
+		gen, err := snowflake.NewHLCGenerator(snowflake.WithClock(clock), snowflake.WithMaxSequence(2))
+		assert.That(err, is.Nil())
+
+		checker := snowflaketest.NewUniquenessChecker()
+		attached := checker.Attach(gen)
+		attached.MustNext()
+
+		clock.Rewind(10)
+		for i := 0; i < 5; i++ {
+			_, err := attached.Next()
+			assert.That(err, is.Nil())
+		}
+		checker.Check(t)
+	})
+
+	t.Run("as sequence provider", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		hlc, err := snowflake.NewHybridLogicalClock(snowflaketest.NewManualClock(100), 1, 10, snowflake.DefaultLayout)
+		assert.That(err, is.Nil())
+
+		gen, err := snowflake.NewGenerator(snowflake.WithSequenceProvider(hlc), snowflake.WithNodeID(1))
+		assert.That(err, is.Nil())
+
+		remote, _ := snowflake.DefaultLayout.Encode(200, 1, 3)
+		hlc.Observe(remote)
+		assert.That(gen.MustNext().ID() > remote.ID(), is.True())
+	})
+
+	t.Run("node out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewHLCGenerator(
+			snowflake.WithLayout(snowflake.Layout{TimestampBits: 44, NodeBits: 4, SequenceBits: 16}),
+			snowflake.WithNodeID(16),
+		)
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDOutOfRange))
+	})
+
+	t.Run("tick marker", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		path := filepath.Join(t.TempDir(), "tick")
+		assert.That(snowflake.NewFileTickMarker(path).SetLastTick(99), is.Nil())
+
+		gen, err := snowflake.NewHLCGenerator(
+			snowflake.WithClock(snowflaketest.NewManualClock(100)),
+			snowflake.WithTickMarker(snowflake.NewFileTickMarker(path)),
+		)
+		assert.That(err, is.Nil())
+		gen.MustNext()
+
+		_, err = snowflake.NewHLCGenerator(
+			snowflake.WithClock(snowflaketest.NewManualClock(100)),
+			snowflake.WithTickMarker(snowflake.NewFileTickMarker(path)),
+			snowflake.WithMaxStartupWait(20*time.Millisecond),
+		)
+		assert.That(errors.Is(err, snowflake.ErrClockBehindMarker), is.True())
+	})
+
+	t.Run("node registry", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		registered := snowflake.WithNodeRegistry(snowflake.RegistryExclusive)
+		gen, err := snowflake.NewHLCGenerator(snowflake.WithNodeID(60), registered)
+		assert.That(err, is.Nil())
+
+		_, err = snowflake.NewHLCGenerator(snowflake.WithNodeID(60), registered)
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDInUse))
+		_, err = snowflake.NewGenerator(snowflake.WithNodeID(60), registered)
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDInUse))
+
+		gen.Observe(gen.MustNext())
+		assert.That(gen.(io.Closer).Close(), is.Nil())
+	})
+
+	t.Run("incompatible options", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		for _, option := range []snowflake.Option{
+			snowflake.WithNodeIDs(1, 2),
+			snowflake.WithSpareNodeIDs(3),
+			snowflake.WithRandomSequenceStart(),
+			snowflake.WithSequenceProvider(snowflaketest.NewScriptedSequenceProvider()),
+		} {
+			_, err := snowflake.NewHLCGenerator(option)
+			assert.That(err, is.EqualTo(snowflake.ErrHybridOptionInvalid))
+		}
+	})
+}
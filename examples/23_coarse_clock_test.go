@@ -0,0 +1,35 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"testing"
+	"time"
+)
+
+func TestCoarseClock(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewCoarseClockWithEpoch(uint64(epoch.Unix()), time.Millisecond)
+	defer clock.Close()
+
+	gen, err := snowflake.NewGenerator(snowflake.WithClock(clock))
+	assert.That(err, is.Nil())
+
+	id := gen.MustNext().(snowflake.ExtendedID)
+	assert.That(snowflake.Since(id) < 2*time.Second, is.True())
+	assert.That(id.Time().Unix()-epoch.Unix(), is.EqualTo(int64(id.Seconds())))
+
+	t.Run("variant", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflake.NewCoarseClock(time.Millisecond)
+		defer clock.Close()
+
+		gen, err := snowflake.NewDiscordCompatible(1, snowflake.WithVariantNow(clock.Now))
+		assert.That(err, is.Nil())
+		assert.That(time.Since(gen.MustNext().(snowflake.ExtendedID).Time()) < time.Second, is.True())
+	})
+}
@@ -0,0 +1,185 @@
+package examples
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"testing"
+	"time"
+)
+
+// scriptedReadings advances wall and monotonic time by the given steps, one step per reading
+type scriptedReadings struct {
+	current snowflake.ClockReading
+	steps   [][2]time.Duration
+}
+
+func (s *scriptedReadings) read() snowflake.ClockReading {
+	if len(s.steps) > 0 {
+		s.current.Wall = s.current.Wall.Add(s.steps[0][0])
+		s.current.Monotonic += s.steps[0][1]
+		s.steps = s.steps[1:]
+	}
+	return s.current
+}
+
+func newScriptedMonitor(t *testing.T, steps [][2]time.Duration, options ...snowflake.MonitorOption) *snowflake.ClockMonitor {
+	readings := &scriptedReadings{
+		current: snowflake.ClockReading{Wall: time.Unix(1647619145, 0)},
+		steps:   append([][2]time.Duration{{0, 0}}, steps...),
+	}
+	monitor, err := snowflake.NewClockMonitor(time.Hour, append(options, snowflake.WithMonitorReadings(readings.read))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = monitor.Close() })
+	return monitor
+}
+
+func TestClockMonitor(t *testing.T) {
+	t.Run("detects", func(t *testing.T) {
+		tests := []struct {
+			name string
+			step [2]time.Duration
+			kind snowflake.ClockEventKind
+		}{
+			{"jump", [2]time.Duration{time.Minute, time.Second}, snowflake.ClockJump},
+			{"rollback", [2]time.Duration{-time.Minute, time.Second}, snowflake.ClockRollback},
+			{"repeat", [2]time.Duration{0, time.Second}, snowflake.ClockRepeat},
+			{"leap second", [2]time.Duration{time.Second, 2 * time.Second}, snowflake.ClockRepeat},
+			{"stall", [2]time.Duration{0, 5 * time.Second}, snowflake.ClockStall},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				assert := hamcrest.NewAssertion(t)
+
+				var reported []snowflake.ClockEvent
+				monitor := newScriptedMonitor(t, [][2]time.Duration{test.step},
+					snowflake.WithMonitorCallback(func(event snowflake.ClockEvent) {
+						reported = append(reported, event)
+					}))
+
+				event, ok := monitor.Check()
+				assert.That(ok, is.True())
+				assert.That(event.Kind, is.EqualTo(test.kind))
+				assert.That(event.Drift, is.EqualTo(test.step[0]-test.step[1]))
+				assert.That(len(reported), is.EqualTo(1))
+				assert.That(errors.Is(monitor.Healthy(), snowflake.ErrClockUntrustworthy), is.True())
+			})
+		}
+	})
+
+	t.Run("every second", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			steps [][2]time.Duration
+			kinds []snowflake.ClockEventKind
+		}{
+			{
+				"stall",
+				[][2]time.Duration{{time.Second, time.Second}, {0, time.Second}, {0, time.Second}, {0, time.Second}},
+				[]snowflake.ClockEventKind{0, snowflake.ClockRepeat, snowflake.ClockStall, snowflake.ClockStall},
+			},
+			{
+				"repeat",
+				[][2]time.Duration{{time.Second, time.Second}, {0, time.Second}, {time.Second, time.Second}, {time.Second, time.Second}},
+				[]snowflake.ClockEventKind{0, snowflake.ClockRepeat, 0, 0},
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				assert := hamcrest.NewAssertion(t)
+
+				monitor := newScriptedMonitor(t, test.steps)
+				for _, kind := range test.kinds {
+					event, _ := monitor.Check()
+					assert.That(event.Kind, is.EqualTo(kind))
+				}
+			})
+		}
+	})
+
+	t.Run("stall across checks", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		steps := make([][2]time.Duration, 60)
+		for i := range steps {
+			steps[i] = [2]time.Duration{0, time.Millisecond}
+		}
+		monitor := newScriptedMonitor(t, steps)
+
+		for i := 0; i < 50; i++ {
+			_, ok := monitor.Check()
+			assert.That(ok, is.False())
+		}
+		event, ok := monitor.Check()
+		assert.That(ok, is.True())
+		assert.That(event.Kind, is.EqualTo(snowflake.ClockStall))
+		assert.That(event.Drift, is.EqualTo(-51*time.Millisecond))
+	})
+
+	t.Run("within tolerance", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		monitor := newScriptedMonitor(t, [][2]time.Duration{
+			{time.Second + 40*time.Millisecond, time.Second},
+			{time.Second, time.Second + 40*time.Millisecond},
+		})
+		_, ok := monitor.Check()
+		assert.That(ok, is.False())
+		_, ok = monitor.Check()
+		assert.That(ok, is.False())
+		assert.That(monitor.Healthy(), is.Nil())
+	})
+
+	t.Run("recovers after clean checks", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		monitor := newScriptedMonitor(t, [][2]time.Duration{
+			{time.Minute, time.Second},
+			{time.Second, time.Second},
+			{time.Second, time.Second},
+		}, snowflake.WithMonitorRecovery(2))
+
+		monitor.Check()
+		monitor.Check()
+		assert.That(monitor.Healthy(), is.NotNil())
+		monitor.Check()
+		assert.That(monitor.Healthy(), is.Nil())
+	})
+
+	t.Run("generator health", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		monitor := newScriptedMonitor(t, [][2]time.Duration{{-time.Minute, time.Second}})
+		gen := snowflake.MustNewGenerator(snowflake.WithClockMonitor(monitor))
+
+		reporter, ok := gen.(snowflake.HealthReporter)
+		assert.That(ok, is.True())
+		assert.That(reporter.Health(), is.Nil())
+
+		monitor.Check()
+		assert.That(errors.Is(reporter.Health(), snowflake.ErrClockUntrustworthy), is.True())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewClockMonitor(0)
+		assert.That(err, is.EqualTo(snowflake.ErrMonitorInvalid))
+		_, err = snowflake.NewClockMonitor(time.Second, snowflake.WithMonitorTolerance(0))
+		assert.That(err, is.EqualTo(snowflake.ErrMonitorInvalid))
+	})
+
+	t.Run("system clock", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		monitor, err := snowflake.NewClockMonitor(time.Millisecond)
+		assert.That(err, is.Nil())
+		defer monitor.Close()
+
+		time.Sleep(20 * time.Millisecond)
+		assert.That(monitor.Healthy(), is.Nil())
+	})
+}
@@ -0,0 +1,95 @@
+package examples
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCorrectedClock(t *testing.T) {
+	t.Run("applies offset", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		source := snowflaketest.NewFakeOffsetSource(-time.Minute, time.Millisecond)
+		clock, err := snowflake.NewCorrectedClock(snowflaketest.NewManualClock(1647619145), source)
+		assert.That(err, is.Nil())
+
+		gen := snowflake.MustNewGenerator(snowflake.WithClock(clock))
+		assert.That(gen.MustNext().Seconds(), is.EqualTo(uint64(1647619085)))
+
+		uncertainty, err := clock.Uncertainty()
+		assert.That(err, is.Nil())
+		assert.That(uncertainty, is.EqualTo(time.Millisecond))
+	})
+
+	t.Run("refuses uncertain clock", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		source := snowflaketest.NewFakeOffsetSource(0, time.Second)
+		clock, err := snowflake.NewCorrectedClock(snowflaketest.NewManualClock(1647619145), source, snowflake.WithOffsetRefresh(0))
+		assert.That(err, is.Nil())
+
+		gen := snowflake.MustNewGenerator(snowflake.WithClock(clock), snowflake.WithMaxUncertainty(10*time.Millisecond, 0))
+		_, err = gen.Next()
+		assert.That(errors.Is(err, snowflake.ErrUncertaintyExceeded), is.True())
+
+		source.Fail(errors.New("agent down"))
+		_, err = gen.Next()
+		assert.That(errors.Is(err, snowflake.ErrUncertaintyExceeded), is.True())
+
+		source.Set(0, time.Millisecond)
+		_, err = gen.Next()
+		assert.That(err, is.Nil())
+	})
+
+	t.Run("waits for uncertainty to drop", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		source := snowflaketest.NewFakeOffsetSource(0, time.Second)
+		clock, err := snowflake.NewCorrectedClock(snowflaketest.NewManualClock(1647619145), source, snowflake.WithOffsetRefresh(0))
+		assert.That(err, is.Nil())
+
+		gen := snowflake.MustNewGenerator(snowflake.WithClock(clock), snowflake.WithMaxUncertainty(10*time.Millisecond, time.Minute))
+		time.AfterFunc(20*time.Millisecond, func() { source.Set(0, time.Millisecond) })
+		_, err = gen.Next()
+		assert.That(err, is.Nil())
+	})
+
+	t.Run("requires corrected clock", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(snowflake.WithMaxUncertainty(time.Millisecond, 0))
+		assert.That(err, is.EqualTo(snowflake.ErrUncertaintyUnavailable))
+	})
+
+	t.Run("file source", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		path := filepath.Join(t.TempDir(), "offset")
+		source := snowflake.NewFileOffsetSource(path, time.Minute)
+
+		_, _, err := source.Offset()
+		assert.That(errors.Is(err, os.ErrNotExist), is.True())
+
+		assert.That(os.WriteFile(path, []byte("-1.5ms 250us\n"), 0o644), is.Nil())
+		offset, uncertainty, err := source.Offset()
+		assert.That(err, is.Nil())
+		assert.That(offset, is.EqualTo(-1500*time.Microsecond))
+		assert.That(uncertainty, is.EqualTo(250*time.Microsecond))
+
+		assert.That(os.WriteFile(path, []byte("1ms"), 0o644), is.Nil())
+		_, _, err = source.Offset()
+		assert.That(errors.Is(err, snowflake.ErrOffsetInvalid), is.True())
+
+		old := time.Now().Add(-time.Hour)
+		assert.That(os.Chtimes(path, old, old), is.Nil())
+		_, _, err = source.Offset()
+		assert.That(errors.Is(err, snowflake.ErrOffsetStale), is.True())
+	})
+}
@@ -0,0 +1,60 @@
+package examples
+
+import (
+	"context"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"testing"
+	"time"
+)
+
+func TestPacing(t *testing.T) {
+	t.Run("generator", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		gen := snowflake.MustNewGenerator(snowflake.WithMaxSequence(100), snowflake.WithPacing(1))
+
+		start := time.Now()
+		for i := 0; i < 11; i++ {
+			gen.MustNext()
+		}
+		assert.That(time.Since(start) >= 90*time.Millisecond, is.True())
+	})
+
+	t.Run("provider", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		provider, err := snowflake.NewPacedSequenceProvider(snowflaketest.NewManualClock(1647619145), 10, 1)
+		assert.That(err, is.Nil())
+		gen := snowflake.MustNewGenerator(snowflake.WithSequenceProvider(provider))
+		assert.That(gen.MustNext().Iteration(), is.EqualTo(uint16(1)))
+
+		reservation := provider.Reserve()
+		assert.That(reservation.Delay() > 50*time.Millisecond, is.True())
+		reservation.Cancel()
+		assert.That(reservation.Sequence().Error, is.EqualTo(snowflake.ErrReservationUsed))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		assert.That(provider.Wait(ctx).Error, is.EqualTo(context.DeadlineExceeded))
+
+		seq := provider.Wait(context.Background())
+		assert.That(seq.Error, is.Nil())
+		assert.That(seq.Iteration, is.EqualTo(uint16(2)))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		_, err := snowflake.NewGenerator(snowflake.WithPacing(0))
+		assert.That(err, is.EqualTo(snowflake.ErrPacingInvalid))
+
+		_, err = snowflake.NewGenerator(snowflake.WithPacing(1), snowflake.WithNodeIDs(1, 2))
+		assert.That(err, is.EqualTo(snowflake.ErrPacingInvalid))
+
+		_, err = snowflake.NewPacedSequenceProvider(snowflake.NewUnixClock(), 0, 1)
+		assert.That(err, is.EqualTo(snowflake.ErrPacingInvalid))
+	})
+}
@@ -0,0 +1,55 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"testing"
+)
+
+func TestRandomSequenceStart(t *testing.T) {
+	t.Run("unique within a second", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflaketest.NewManualClock(1647619145)
+		gen := snowflake.MustNewGenerator(
+			snowflake.WithClock(clock),
+			snowflake.WithMaxSequence(1000),
+			snowflake.WithRandomSequenceStart(),
+		)
+
+		starts := map[uint16]struct{}{}
+		seen := map[uint64]struct{}{}
+		for second := 0; second < 10; second++ {
+			for i := 0; i < 1000; i++ {
+				id := gen.MustNext()
+				if i == 0 {
+					starts[id.Iteration()] = struct{}{}
+				}
+				assert.That(id.Iteration() >= 1 && id.Iteration() <= 1000, is.True())
+				seen[id.ID()] = struct{}{}
+			}
+			clock.Advance(1)
+		}
+		assert.That(len(seen), is.EqualTo(10000))
+		assert.That(len(starts) > 1, is.True())
+	})
+
+	t.Run("node ids", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		gen := snowflake.MustNewGenerator(
+			snowflake.WithClock(snowflaketest.NewManualClock(1647619145)),
+			snowflake.WithMaxSequence(100),
+			snowflake.WithNodeIDs(1, 2),
+			snowflake.WithRandomSequenceStart(),
+		)
+
+		seen := map[uint64]struct{}{}
+		for i := 0; i < 200; i++ {
+			seen[gen.MustNext().ID()] = struct{}{}
+		}
+		assert.That(len(seen), is.EqualTo(200))
+	})
+}
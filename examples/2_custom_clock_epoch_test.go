@@ -5,23 +5,15 @@ import (
 	"github.com/scarabsoft/go-hamcrest"
 	"github.com/scarabsoft/go-hamcrest/is"
 	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
 	"testing"
 )
 
-type incrementClockImpl struct {
-	value uint64
-}
-
-func (i incrementClockImpl) Seconds() uint64 {
-	i.value++
-	return i.value
-}
-
 func TestCustomClockEpoch(t *testing.T) {
 	assert := hamcrest.NewAssertion(t)
 
 	gen, err := snowflake.NewGenerator(
-		snowflake.WithClock(incrementClockImpl{}),
+		snowflake.WithClock(snowflaketest.NewManualClock(1)),
 		snowflake.WithNodeID(1),
 	)
 
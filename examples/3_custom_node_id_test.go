@@ -6,21 +6,14 @@ import (
 	"github.com/scarabsoft/go-hamcrest/has"
 	"github.com/scarabsoft/go-hamcrest/is"
 	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
 	"testing"
 )
 
-type fakeClock struct {
-	value uint64
-}
-
-func (f fakeClock) Seconds() uint64 {
-	return f.value
-}
-
 func TestCustomNodeId(t *testing.T) {
 	assert := hamcrest.NewAssertion(t)
 
-	clock := fakeClock{1337}
+	clock := snowflaketest.NewManualClock(1337)
 
 	nodeGen1, err := snowflake.NewGenerator(
 		snowflake.WithNodeID(1),
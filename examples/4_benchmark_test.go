@@ -5,6 +5,7 @@ import (
 	"runtime"
 	"sync"
 	"testing"
+	"time"
 )
 
 var cores = runtime.NumCPU()
@@ -29,3 +30,50 @@ func BenchmarkTestBenchmark_Parallel(b *testing.B) {
 	}
 	wg.Wait()
 }
+
+// ids is the number of IDs a generator hands out within a second, the benchmarks replace a generator before it
+// exhausts its sequence so they measure Next instead of the wait for the next second
+var ids = int(snowflake.DefaultLayout.MaxSequence())
+
+func benchmarkNext(b *testing.B, clock snowflake.Clock) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i += ids {
+		g := snowflake.MustNewGenerator(snowflake.WithClock(clock))
+		for j := i; j < b.N && j < i+ids; j++ {
+			_, _ = g.Next()
+		}
+	}
+}
+
+func benchmarkNextParallel(b *testing.B, clock snowflake.Clock) {
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var g snowflake.Generator
+		for i := 0; pb.Next(); i++ {
+			if i%ids == 0 {
+				g = snowflake.MustNewGenerator(snowflake.WithClock(clock))
+			}
+			_, _ = g.Next()
+		}
+	})
+}
+
+func BenchmarkTestBenchmark_UnixClock(b *testing.B) {
+	benchmarkNext(b, snowflake.NewUnixClock())
+}
+
+func BenchmarkTestBenchmark_CoarseClock(b *testing.B) {
+	clock := snowflake.NewCoarseClock(time.Millisecond)
+	defer clock.Close()
+	benchmarkNext(b, clock)
+}
+
+func BenchmarkTestBenchmark_UnixClock_Parallel(b *testing.B) {
+	benchmarkNextParallel(b, snowflake.NewUnixClock())
+}
+
+func BenchmarkTestBenchmark_CoarseClock_Parallel(b *testing.B) {
+	clock := snowflake.NewCoarseClock(time.Millisecond)
+	defer clock.Close()
+	benchmarkNextParallel(b, clock)
+}
@@ -4,22 +4,15 @@ import (
 	"github.com/scarabsoft/go-hamcrest"
 	"github.com/scarabsoft/go-hamcrest/is"
 	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
 	"testing"
 )
 
-type fakeClockImpl struct {
-	value uint64
-}
-
-func (f fakeClockImpl) Seconds() uint64 {
-	return f.value
-}
-
 func TestID(t *testing.T) {
 	assert := hamcrest.NewAssertion(t)
 
 	gen, err := snowflake.NewGenerator(
-		snowflake.WithClock(fakeClockImpl{value: 1647619145}),
+		snowflake.WithClock(snowflaketest.NewManualClock(1647619145)),
 		snowflake.WithNodeID(128),
 	)
 	assert.That(err, is.Nil())
@@ -19,4 +19,12 @@ func TestNewID(t *testing.T) {
 	result := snowflake.MustNewID()
 	assert.That(result.ID(), is.EqualTo(uint64(376833)))
 
+	// the default generator is reused, so IDs of the same second do not collide
+	next := snowflake.MustNewID()
+	assert.That(next.ID(), is.GreaterThan(result.ID()))
+	assert.That(next.NodeID(), is.EqualTo(uint8(23)))
+
+	gen, err := snowflake.DefaultGenerator()
+	assert.That(err, is.Nil())
+	assert.That(gen.MustNext().ID(), is.GreaterThan(next.ID()))
 }
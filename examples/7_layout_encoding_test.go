@@ -0,0 +1,97 @@
+package examples
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"testing"
+)
+
+func TestCustomLayout(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	layout, err := snowflake.ParseLayout("44:4:16")
+	assert.That(err, is.Nil())
+
+	gen, err := snowflake.NewGenerator(
+		snowflake.WithLayout(layout),
+		snowflake.WithClock(snowflaketest.NewManualClock(1647619145)),
+		snowflake.WithNodeID(15),
+	)
+	assert.That(err, is.Nil())
+
+	r, err := gen.Next()
+	assert.That(err, is.Nil())
+	assert.That(r.Seconds(), is.EqualTo(uint64(1647619145)))
+	assert.That(r.NodeID(), is.EqualTo(uint8(15)))
+	assert.That(r.Iteration(), is.EqualTo(uint16(1)))
+
+	decoded := layout.From(r.ID())
+	assert.That(decoded.Seconds(), is.EqualTo(uint64(1647619145)))
+	assert.That(decoded.NodeID(), is.EqualTo(uint8(15)))
+}
+
+func TestCustomLayout_Invalid(t *testing.T) {
+	t.Run("layout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := snowflake.NewGenerator(snowflake.WithLayout(snowflake.Layout{TimestampBits: 42}))
+		assert.That(err, is.EqualTo(snowflake.ErrLayoutInvalid))
+	})
+	t.Run("node id", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := snowflake.NewGenerator(
+			snowflake.WithLayout(snowflake.Layout{TimestampBits: 44, NodeBits: 4, SequenceBits: 16}),
+			snowflake.WithNodeID(16),
+		)
+		assert.That(err, is.EqualTo(snowflake.ErrNodeIDOutOfRange))
+	})
+	t.Run("max sequence", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := snowflake.NewGenerator(
+			snowflake.WithLayout(snowflake.Layout{TimestampBits: 46, NodeBits: 8, SequenceBits: 10}),
+			snowflake.WithMaxSequence(1024),
+		)
+		assert.That(err, is.EqualTo(snowflake.ErrMaxSequenceOutOfRange))
+	})
+}
+
+func TestEncoding(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	id := uint64(6910615572447233)
+	expected := map[string]string{
+		"decimal": "6910615572447233",
+		"hex":     "188d2b12600001",
+		"base32":  "64d5c960001",
+		"base62":  "VeLGH3LIP",
+	}
+
+	for name, formatted := range expected {
+		encoding, err := snowflake.ParseEncoding(name)
+		assert.That(err, is.Nil())
+		assert.That(encoding.String(), is.EqualTo(name))
+		assert.That(encoding.Format(id), is.EqualTo(formatted))
+
+		r, err := encoding.Parse(formatted)
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(id))
+	}
+
+	_, err := snowflake.EncodingHex.Parse("xyz")
+	assert.That(err, is.EqualTo(snowflake.ErrInvalidEncoding))
+}
+
+func TestLayout_Encode(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	id, err := snowflake.DefaultLayout.Encode(1647619145, 128, 1)
+	assert.That(err, is.Nil())
+	assert.That(id.ID(), is.EqualTo(uint64(6910615572447233)))
+
+	_, err = snowflake.DefaultLayout.Encode(1<<42, 128, 1)
+	assert.That(err, is.EqualTo(snowflake.ErrTimestampOverflow))
+
+	_, err = snowflake.DefaultLayout.Encode(1647619145, 128, 1<<14)
+	assert.That(err, is.EqualTo(snowflake.ErrSequenceOutOfRange))
+}
@@ -0,0 +1,225 @@
+package examples
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"github.com/scarabsoft/go-snowflake/wire"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeBlockSource struct {
+	lock     sync.Mutex
+	next     uint64
+	requests []int
+	err      error
+}
+
+func (f *fakeBlockSource) Block(n int) ([]uint64, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.requests = append(f.requests, n)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	r := make([]uint64, n)
+	for i := range r {
+		f.next++
+		r[i] = f.next
+	}
+	return r, nil
+}
+
+func (f *fakeBlockSource) setErr(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.err = err
+}
+
+func (f *fakeBlockSource) requestCount() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return len(f.requests)
+}
+
+func TestBufferedRemoteGenerator(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	source := &fakeBlockSource{}
+
+	gen, err := snowflake.NewBufferedRemoteGenerator(source, 2, 5)
+	assert.That(err, is.Nil())
+	defer gen.Close()
+
+	for i := 1; i <= 20; i++ {
+		id, err := gen.Next()
+		assert.That(err, is.Nil())
+		assert.That(id.ID(), is.EqualTo(uint64(i)))
+	}
+	assert.That(source.requestCount(), is.GreaterThan(1))
+}
+
+func TestBufferedRemoteGenerator_InvalidWatermarks(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	for _, watermarks := range [][2]int{{-1, 5}, {5, 5}, {6, 5}, {0, 0}} {
+		_, err := snowflake.NewBufferedRemoteGenerator(&fakeBlockSource{}, watermarks[0], watermarks[1])
+		assert.That(err, is.EqualTo(snowflake.ErrWatermarksInvalid))
+	}
+}
+
+func TestBufferedRemoteGenerator_Unavailable(t *testing.T) {
+	boom := errors.New("boom")
+
+	t.Run("fail fast", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		source := &fakeBlockSource{err: boom}
+
+		gen, err := snowflake.NewBufferedRemoteGenerator(source, 1, 2, snowflake.WithBufferFailFast())
+		assert.That(err, is.Nil())
+		defer gen.Close()
+
+		_, err = gen.Next()
+		assert.That(errors.Is(err, snowflake.ErrSourceUnavailable), is.True())
+		assert.That(err.Error(), is.EqualTo("block source is unavailable: boom"))
+	})
+	t.Run("wait timeout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		source := &fakeBlockSource{err: boom}
+
+		gen, err := snowflake.NewBufferedRemoteGenerator(source, 1, 2, snowflake.WithBufferWaitTimeout(10*time.Millisecond))
+		assert.That(err, is.Nil())
+		defer gen.Close()
+
+		_, err = gen.Next()
+		assert.That(errors.Is(err, snowflake.ErrSourceUnavailable), is.True())
+	})
+	t.Run("wait until recovered", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		source := &fakeBlockSource{err: boom}
+
+		gen, err := snowflake.NewBufferedRemoteGenerator(source, 1, 2, snowflake.WithBufferRetryInterval(time.Millisecond))
+		assert.That(err, is.Nil())
+		defer gen.Close()
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			source.setErr(nil)
+		}()
+
+		id, err := gen.Next()
+		assert.That(err, is.Nil())
+		assert.That(id.ID(), is.EqualTo(uint64(1)))
+	})
+}
+
+func TestBufferedRemoteGenerator_Close(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	gen, err := snowflake.NewBufferedRemoteGenerator(&fakeBlockSource{}, 1, 2)
+	assert.That(err, is.Nil())
+	assert.That(gen.Close(), is.Nil())
+	assert.That(gen.Close(), is.Nil())
+
+	_, err = gen.Next()
+	assert.That(err, is.EqualTo(snowflake.ErrGeneratorClosed))
+}
+
+// blockingBlockSource blocks every Block call until release is closed
+type blockingBlockSource struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingBlockSource) Block(n int) ([]uint64, error) {
+	close(b.entered)
+	<-b.release
+	return []uint64{1}, nil
+}
+
+func TestBufferedRemoteGenerator_CloseWhileBlocked(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	source := &blockingBlockSource{entered: make(chan struct{}), release: make(chan struct{})}
+	defer close(source.release)
+
+	gen, err := snowflake.NewBufferedRemoteGenerator(source, 1, 2)
+	assert.That(err, is.Nil())
+	<-source.entered
+
+	closed := make(chan error)
+	go func() { closed <- gen.Close() }()
+	select {
+	case err := <-closed:
+		assert.That(err, is.Nil())
+	case <-time.After(time.Second):
+		t.Fatal("close waits for the blocked source")
+	}
+}
+
+func TestBufferedRemoteGenerator_Options(t *testing.T) {
+	t.Run("retry interval", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		for _, interval := range []time.Duration{0, -time.Millisecond} {
+			_, err := snowflake.NewBufferedRemoteGenerator(&fakeBlockSource{}, 1, 2, snowflake.WithBufferRetryInterval(interval))
+			assert.That(err, is.EqualTo(snowflake.ErrRetryIntervalInvalid))
+		}
+	})
+
+	t.Run("codec", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		options := []snowflake.Option{snowflake.WithClock(snowflake.NewUnixClockWithEpoch(1647619145))}
+		codec, err := snowflake.NewCodec(options...)
+		assert.That(err, is.Nil())
+		id, err := codec.Encode(time.Unix(1647619145+60, 0), 3, 1)
+		assert.That(err, is.Nil())
+
+		source := &fakeBlockSource{next: id.ID() - 1}
+		gen, err := snowflake.NewBufferedRemoteGenerator(source, 1, 2, snowflake.WithBufferCodec(codec))
+		assert.That(err, is.Nil())
+		defer gen.Close()
+
+		r := gen.MustNext().(snowflake.ExtendedID)
+		assert.That(r.ID(), is.EqualTo(id.ID()))
+		assert.That(r.Time(), is.EqualTo(time.Unix(1647619145+60, 0).UTC()))
+	})
+}
+
+func TestBufferedRemoteGenerator_Wire(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	srv, err := wire.NewServer(snowflake.MustNewGenerator(
+		snowflake.WithClock(snowflaketest.NewManualClock(1337)),
+		snowflake.WithNodeID(7),
+	))
+	assert.That(err, is.Nil())
+	defer srv.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.That(err, is.Nil())
+	go func() {
+		_ = srv.Serve(l)
+	}()
+
+	client, err := wire.Dial("tcp", l.Addr().String())
+	assert.That(err, is.Nil())
+	defer client.Close()
+
+	gen, err := snowflake.NewBufferedRemoteGenerator(client, 10, 100)
+	assert.That(err, is.Nil())
+	defer gen.Close()
+
+	var prev uint64
+	for i := 0; i < 250; i++ {
+		id, err := gen.Next()
+		assert.That(err, is.Nil())
+		assert.That(id.NodeID(), is.EqualTo(uint8(7)))
+		assert.That(id.ID(), is.GreaterThan(prev))
+		prev = id.ID()
+	}
+}
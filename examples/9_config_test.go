@@ -0,0 +1,163 @@
+package examples
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withEnv sets the snowflake environment variables for the duration of the test, nil values get unset
+func withEnv(t *testing.T, env map[string]*string) {
+	for _, key := range []string{
+		snowflake.EnvNodeID,
+		snowflake.EnvGenesisEpochSeconds,
+		snowflake.EnvLayout,
+		snowflake.EnvMaxSequence,
+		snowflake.EnvConfigFile,
+	} {
+		prev, found := os.LookupEnv(key)
+		if value := env[key]; value != nil {
+			_ = os.Setenv(key, *value)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+
+		key := key
+		t.Cleanup(func() {
+			if found {
+				_ = os.Setenv(key, prev)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		})
+	}
+}
+
+func str(s string) *string {
+	return &s
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		withEnv(t, nil)
+
+		r, err := snowflake.ConfigFromEnv()
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(snowflake.DefaultConfig()))
+	})
+	t.Run("variables", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		withEnv(t, map[string]*string{
+			snowflake.EnvNodeID:              str("15"),
+			snowflake.EnvGenesisEpochSeconds: str("1288834974"),
+			snowflake.EnvLayout:              str("44:4:16"),
+			snowflake.EnvMaxSequence:         str("1000"),
+		})
+
+		r, err := snowflake.ConfigFromEnv()
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(snowflake.Config{NodeID: 15, Epoch: 1288834974, Layout: "44:4:16", MaxSequence: 1000}))
+	})
+	t.Run("file overridden by variables", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		withEnv(t, map[string]*string{
+			snowflake.EnvConfigFile: str(writeFile(t, "snowflake.yaml", "node_id: 3\nepoch: 60\n")),
+			snowflake.EnvNodeID:     str("4"),
+		})
+
+		r, err := snowflake.ConfigFromEnv()
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(snowflake.Config{NodeID: 4, Epoch: 60, Layout: "42:8:14"}))
+	})
+	t.Run("node id is not a number", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		withEnv(t, map[string]*string{snowflake.EnvNodeID: str("abc")})
+
+		_, err := snowflake.ConfigFromEnv()
+		assert.That(errors.Is(err, snowflake.ErrConfigInvalid), is.True())
+		assert.That(err.Error(), is.EqualTo(`invalid config: NODE_ID "abc" is not a number`))
+	})
+	t.Run("node id exceeds layout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		withEnv(t, map[string]*string{snowflake.EnvNodeID: str("256")})
+
+		_, err := snowflake.ConfigFromEnv()
+		assert.That(errors.Is(err, snowflake.ErrConfigInvalid), is.True())
+		assert.That(err.Error(), is.EqualTo("invalid config: node_id 256 is not between 0 and 255 of layout 42:8:14"))
+	})
+	t.Run("negative epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		withEnv(t, map[string]*string{snowflake.EnvGenesisEpochSeconds: str("-1")})
+
+		_, err := snowflake.ConfigFromEnv()
+		assert.That(errors.Is(err, snowflake.ErrConfigInvalid), is.True())
+	})
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := snowflake.LoadConfigFile(writeFile(t, "snowflake.json", `{"node_id": 7, "layout": "44:4:16", "max_sequence": 100}`))
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(snowflake.Config{NodeID: 7, Layout: "44:4:16", MaxSequence: 100}))
+	})
+	t.Run("yaml", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := snowflake.LoadConfigFile(writeFile(t, "snowflake.yml", "epoch: 1288834974\n"))
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(snowflake.Config{NodeID: 1, Epoch: 1288834974, Layout: "42:8:14"}))
+	})
+	t.Run("malformed", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := snowflake.LoadConfigFile(writeFile(t, "snowflake.json", `{"node_id": "seven"}`))
+		assert.That(errors.Is(err, snowflake.ErrConfigInvalid), is.True())
+	})
+	t.Run("invalid layout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := snowflake.LoadConfigFile(writeFile(t, "snowflake.yaml", "layout: 40:8:14\n"))
+		assert.That(errors.Is(err, snowflake.ErrConfigInvalid), is.True())
+	})
+	t.Run("max sequence exceeds layout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := snowflake.LoadConfigFile(writeFile(t, "snowflake.yaml", "layout: 46:8:10\nmax_sequence: 1024\n"))
+		assert.That(err.Error(), is.EqualTo("invalid config: max_sequence 1024 is not between 0 and 1023 of layout 46:8:10"))
+	})
+	t.Run("missing", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := snowflake.LoadConfigFile(filepath.Join(t.TempDir(), "missing.json"))
+		assert.That(os.IsNotExist(err), is.True())
+	})
+}
+
+func TestNewGeneratorFromConfig(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	gen, err := snowflake.NewGeneratorFromConfig(
+		snowflake.Config{NodeID: 15, Layout: "44:4:16"},
+		snowflake.WithClock(snowflaketest.NewManualClock(1647619145)),
+	)
+	assert.That(err, is.Nil())
+
+	r, err := gen.Next()
+	assert.That(err, is.Nil())
+	assert.That(r.Seconds(), is.EqualTo(uint64(1647619145)))
+	assert.That(r.NodeID(), is.EqualTo(uint8(15)))
+
+	_, err = snowflake.NewGeneratorFromConfig(snowflake.Config{NodeID: 16, Layout: "44:4:16"})
+	assert.That(errors.Is(err, snowflake.ErrConfigInvalid), is.True())
+}
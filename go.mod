@@ -1,5 +1,8 @@
 module github.com/scarabsoft/go-snowflake
 
-go 1.16
+go 1.18
 
-require github.com/scarabsoft/go-hamcrest v0.1.6
+require (
+	github.com/scarabsoft/go-hamcrest v0.1.6
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,161 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"github.com/scarabsoft/go-snowflake/internal"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// startupGuardPoll is how often the startup guard checks whether the clock left the guarded tick
+const startupGuardPoll = 10 * time.Millisecond
+
+// DefaultMaxStartupWait is how long the startup guard waits for the clock at most, see WithMaxStartupWait
+const DefaultMaxStartupWait = 5 * time.Second
+
+// TickMarker persists the last tick a generator issued IDs in, so a restarted generator knows whether it has to wait
+type TickMarker interface {
+	// LastTick returns the last persisted tick, false if no tick was persisted yet
+	LastTick() (uint64, bool, error)
+	// SetLastTick persists the tick, it is invoked once per tick
+	SetLastTick(tick uint64) error
+}
+
+type fileTickMarkerImpl struct {
+	path string
+}
+
+// NewFileTickMarker returns a TickMarker storing the tick in the file at path, the file is replaced atomically
+func NewFileTickMarker(path string) TickMarker {
+	return &fileTickMarkerImpl{path: path}
+}
+
+func (f *fileTickMarkerImpl) LastTick() (uint64, bool, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	tick, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("tick marker %s is corrupt: %w", f.path, err)
+	}
+	return tick, true, nil
+}
+
+func (f *fileTickMarkerImpl) SetLastTick(tick uint64) error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.WriteString(strconv.FormatUint(tick, 10)); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// WithStartupGuard makes NewGenerator wait until the clock left the current tick, so a generator which was restarted
+// within the tick of its last ID does not reissue its IDs. Has no effect with WithSequenceProvider
+func WithStartupGuard() Option {
+	return func(impl *generatorBuilderImpl) error {
+		impl.startupGuard = true
+		return nil
+	}
+}
+
+// WithTickMarker enables the startup guard and persists the last tick in marker. NewGenerator only waits
+// until the clock passed the persisted tick, which skips the wait if the last ID was issued in an earlier tick
+func WithTickMarker(marker TickMarker) Option {
+	return func(impl *generatorBuilderImpl) error {
+		impl.startupGuard = true
+		impl.tickMarker = marker
+		return nil
+	}
+}
+
+// WithMaxStartupWait limits how long the startup guard waits for the clock to pass the guarded tick, NewGenerator
+// fails with ErrClockBehindMarker once it runs out, e.g. because the persisted tick is far ahead of the clock.
+// By default DefaultMaxStartupWait
+func WithMaxStartupWait(wait time.Duration) Option {
+	return func(impl *generatorBuilderImpl) error {
+		if wait <= 0 {
+			return ErrStartupWaitInvalid
+		}
+		impl.maxStartupWait = wait
+		return nil
+	}
+}
+
+// guardStartup blocks until clock passed the last tick of the marker, or the current tick if there is no marker.
+// It gives up with ErrClockBehindMarker after maxWait
+func guardStartup(clock Clock, marker TickMarker, maxWait time.Duration) error {
+	last := clock.Seconds()
+	if marker != nil {
+		tick, ok, err := marker.LastTick()
+		if err != nil {
+			return err
+		}
+		if ok {
+			last = tick
+		}
+	}
+
+	deadline := time.Now().Add(maxWait)
+	for {
+		current := clock.Seconds()
+		if current > last {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("%w: clock at tick %d, guarded tick %d", ErrClockBehindMarker, current, last)
+		}
+		time.Sleep(startupGuardPoll)
+	}
+}
+
+// markingGeneratorImpl persists every new tick of the wrapped generator before its ID is handed out
+type markingGeneratorImpl struct {
+	gen    internal.SnowflakeGenerator
+	layout internal.Layout
+	marker TickMarker
+
+	lock   sync.Mutex
+	marked uint64
+}
+
+func (m *markingGeneratorImpl) Next() (uint64, error) {
+	id, err := m.gen.Next()
+	if err != nil {
+		return 0, err
+	}
+
+	tick, _, _ := m.layout.Decode(id)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if tick > m.marked {
+		if err := m.marker.SetLastTick(tick); err != nil {
+			return 0, err
+		}
+		m.marked = tick
+	}
+	return id, nil
+}
@@ -0,0 +1,90 @@
+package snowflake
+
+import (
+	"github.com/scarabsoft/go-snowflake/internal"
+)
+
+// HybridLogicalClock is a SequenceProvider whose timestamp is the max of the clock and all observed IDs.
+// It never fails on clock rollbacks, it keeps counting the iteration and moves to the next tick ahead of the clock
+type HybridLogicalClock interface {
+	SequenceProvider
+	// Observe advances the logical time past remote, an ID of a generator with the same epoch and layout
+	Observe(remote ID)
+}
+
+type hybridLogicalClockImpl struct {
+	hlc interface {
+		internal.SequenceProvider
+		Observe(seconds uint64, nodeID uint8, iteration uint16)
+	}
+}
+
+func (h hybridLogicalClockImpl) Sequence() Sequence {
+	return h.hlc.Sequence()
+}
+
+func (h hybridLogicalClockImpl) Observe(remote ID) {
+	h.hlc.Observe(remote.Seconds(), remote.NodeID(), remote.Iteration())
+}
+
+// NewHybridLogicalClock returns a hybrid logical clock for the node nodeID, maxSequence is the logical counter per tick
+func NewHybridLogicalClock(clock Clock, nodeID uint8, maxSequence uint16, layout Layout) (HybridLogicalClock, error) {
+	impl, err := internal.NewHLCSequenceProvider(clock, maxSequence, nodeID, layout.internal())
+	if err != nil {
+		return nil, err
+	}
+	return hybridLogicalClockImpl{impl}, nil
+}
+
+// HLCGenerator is a Generator driven by a HybridLogicalClock, every ID issued after Observe is greater than the observed ID
+type HLCGenerator interface {
+	Generator
+	Observe(remote ID)
+}
+
+type hlcGeneratorImpl struct {
+	*generatorImpl
+}
+
+func (h *hlcGeneratorImpl) Observe(remote ID) {
+	h.hlc.Observe(remote)
+}
+
+// registeredHLCGeneratorImpl is a HLCGenerator created with WithNodeRegistry, it implements io.Closer
+type registeredHLCGeneratorImpl struct {
+	*registeredGeneratorImpl
+}
+
+func (h *registeredHLCGeneratorImpl) Observe(remote ID) {
+	h.hlc.Observe(remote)
+}
+
+// NewHLCGenerator returns a generator using a HybridLogicalClock on top of the clock, so IDs respect happens-before
+// between nodes which exchange their IDs. It fails with ErrHybridOptionInvalid when combined with
+// WithSequenceProvider, WithNodeIDs, WithSpareNodeIDs or WithRandomSequenceStart
+func NewHLCGenerator(options ...Option) (HLCGenerator, error) {
+	r, err := newGeneratorBuilder(options...)
+	if err != nil {
+		return nil, err
+	}
+	if r.seqProvider != nil {
+		return nil, ErrHybridOptionInvalid
+	}
+
+	r.hybrid = true
+	r.nodeProvider = NewFixedNodeProvider(r.nodeProvider.ID())
+
+	if r.registryMode != 0 {
+		gen, err := newRegisteredGenerator(r)
+		if err != nil {
+			return nil, err
+		}
+		return &registeredHLCGeneratorImpl{gen}, nil
+	}
+
+	gen, err := newGenerator(r)
+	if err != nil {
+		return nil, err
+	}
+	return &hlcGeneratorImpl{gen}, nil
+}
@@ -12,10 +12,59 @@ type unixClockImpl struct {
 	customEpoch uint64
 }
 
+// Seconds returns the seconds passed since the epoch, 0 if the epoch is still in the future
 func (u unixClockImpl) Seconds() uint64 {
-	return uint64(time.Now().Unix()) - u.customEpoch
+	now := uint64(time.Now().Unix())
+	if now < u.customEpoch {
+		return 0
+	}
+	return now - u.customEpoch
+}
+
+// Now returns the current time
+func (u unixClockImpl) Now() time.Time {
+	return time.Now()
+}
+
+// Epoch returns the epoch in seconds since the UNIX epoch
+func (u unixClockImpl) Epoch() uint64 {
+	return u.customEpoch
+}
+
+// EpochClock is implemented by clocks counting from a fixed epoch, it allows validating the epoch up front
+type EpochClock interface {
+	Clock
+	Epoch() uint64
+}
+
+// CheckEpoch returns ErrEpochInFuture if clock counts from an epoch which has not been reached yet
+func CheckEpoch(clock Clock) error {
+	if c, ok := clock.(EpochClock); ok && c.Epoch() > uint64(time.Now().Unix()) {
+		return ErrEpochInFuture
+	}
+	return nil
 }
 
 func NewUnixClockWithEpoch(epoch uint64) Clock {
 	return &unixClockImpl{customEpoch: epoch}
 }
+
+type tickClockImpl struct {
+	epoch time.Time
+	unit  time.Duration
+	now   func() time.Time
+}
+
+// Seconds returns the ticks of unit passed since the epoch, 0 if the epoch is still in the future
+func (t tickClockImpl) Seconds() uint64 {
+	now := t.now()
+	if now.Before(t.epoch) {
+		return 0
+	}
+	return uint64(now.Sub(t.epoch) / t.unit)
+}
+
+// NewTickClock returns a clock counting ticks of unit since epoch instead of seconds
+func NewTickClock(epoch time.Time, unit time.Duration, now func() time.Time) Clock {
+	return &tickClockImpl{epoch: epoch, unit: unit, now: now}
+}
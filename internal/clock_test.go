@@ -29,3 +29,44 @@ func TestNewUnixClockWithEpoch(t *testing.T) {
 		assert.That(r, is.EqualTo(uint64(0)))
 	})
 }
+
+func TestUnixClockImpl_EpochInFuture(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	testInstance := NewUnixClockWithEpoch(uint64(time.Now().Add(time.Hour).Unix()))
+	assert.That(testInstance.Seconds(), is.EqualTo(uint64(0)))
+}
+
+func TestCheckEpoch(t *testing.T) {
+	t.Run("epoch in the past", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(CheckEpoch(NewUnixClockWithEpoch(1288834974)), is.Nil())
+	})
+	t.Run("epoch in the future", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		// an epoch in nanoseconds is far in the future when interpreted as seconds
+		assert.That(CheckEpoch(NewUnixClockWithEpoch(uint64(time.Now().UnixNano()))), is.EqualTo(ErrEpochInFuture))
+	})
+	t.Run("clock without epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(CheckEpoch(fakeClock{10}), is.Nil())
+	})
+}
+
+func TestNewTickClock(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("ticks since epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance := NewTickClock(epoch, 10*time.Millisecond, func() time.Time { return epoch.Add(1234 * time.Millisecond) })
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(123)))
+	})
+
+	t.Run("epoch in future", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance := NewTickClock(epoch, time.Millisecond, func() time.Time { return epoch.Add(-time.Second) })
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(0)))
+	})
+}
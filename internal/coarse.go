@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// coarseCheckInterval is the number of reads after which the cached time is compared against time.Now
+const coarseCheckInterval = 1024
+
+// coarseClockImpl caches the current time, a background ticker refreshes it every resolution
+// so reading the clock does not call time.Now. If the ticker lags behind, a read falls back to time.Now
+type coarseClockImpl struct {
+	customEpoch uint64
+	resolution  time.Duration
+
+	now     int64
+	reads   uint32
+	stopped int32
+
+	done chan struct{}
+	once sync.Once
+}
+
+// Seconds returns the seconds passed since the epoch, 0 if the epoch is still in the future
+func (c *coarseClockImpl) Seconds() uint64 {
+	now := uint64(c.Now().Unix())
+	if now < c.customEpoch {
+		return 0
+	}
+	return now - c.customEpoch
+}
+
+// Now returns the cached time, every coarseCheckInterval reads it falls back to time.Now if the cached time is more
+// than twice the resolution old. Once the clock was closed every read falls back to time.Now
+func (c *coarseClockImpl) Now() time.Time {
+	if atomic.LoadInt32(&c.stopped) == 1 {
+		c.store(time.Now().UnixNano())
+	} else if atomic.AddUint32(&c.reads, 1)%coarseCheckInterval == 0 {
+		if now := time.Now().UnixNano(); now-atomic.LoadInt64(&c.now) > 2*int64(c.resolution) {
+			c.store(now)
+		}
+	}
+	return time.Unix(0, atomic.LoadInt64(&c.now))
+}
+
+// Epoch returns the epoch in seconds since the UNIX epoch
+func (c *coarseClockImpl) Epoch() uint64 {
+	return c.customEpoch
+}
+
+// Close stops the background ticker, afterwards every read falls back to time.Now
+func (c *coarseClockImpl) Close() error {
+	c.once.Do(func() {
+		atomic.StoreInt32(&c.stopped, 1)
+		close(c.done)
+	})
+	return nil
+}
+
+// store moves the cached time forward, it never moves it backwards
+func (c *coarseClockImpl) store(now int64) {
+	for {
+		cached := atomic.LoadInt64(&c.now)
+		if now <= cached || atomic.CompareAndSwapInt64(&c.now, cached, now) {
+			return
+		}
+	}
+}
+
+func (c *coarseClockImpl) run() {
+	ticker := time.NewTicker(c.resolution)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.store(time.Now().UnixNano())
+		}
+	}
+}
+
+// NewCoarseClockWithEpoch returns and starts a clock caching the time with the given resolution,
+// can be stopped by invoking Close()
+func NewCoarseClockWithEpoch(epoch uint64, resolution time.Duration) *coarseClockImpl {
+	r := &coarseClockImpl{
+		customEpoch: epoch,
+		resolution:  resolution,
+		now:         time.Now().UnixNano(),
+		done:        make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
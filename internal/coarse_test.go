@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"testing"
+	"time"
+)
+
+func TestCoarseClock(t *testing.T) {
+	t.Run("seconds", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance := NewCoarseClockWithEpoch(0, time.Millisecond)
+		defer testInstance.Close()
+
+		before := uint64(time.Now().Unix())
+		r := testInstance.Seconds()
+		assert.That(r >= before, is.True())
+		assert.That(r <= uint64(time.Now().Unix()), is.True())
+	})
+
+	t.Run("epoch in future", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance := NewCoarseClockWithEpoch(uint64(time.Now().Add(time.Hour).Unix()), time.Millisecond)
+		defer testInstance.Close()
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(0)))
+	})
+
+	t.Run("ticker refreshes", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance := NewCoarseClockWithEpoch(0, time.Millisecond)
+		defer testInstance.Close()
+
+		first := testInstance.Now()
+		time.Sleep(20 * time.Millisecond)
+		assert.That(testInstance.Now().After(first), is.True())
+	})
+
+	t.Run("fallback after close", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance := NewCoarseClockWithEpoch(0, time.Millisecond)
+		assert.That(testInstance.Close(), is.Nil())
+		assert.That(testInstance.Close(), is.Nil())
+
+		time.Sleep(20 * time.Millisecond)
+		now := time.Now()
+		assert.That(testInstance.Now().Before(now), is.False())
+	})
+
+	t.Run("fallback if the ticker stalls", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		// no ticker is running, the cached time stays a second behind until a read falls back
+		stalled := time.Now().Add(-time.Second)
+		testInstance := &coarseClockImpl{
+			resolution: time.Millisecond,
+			now:        stalled.UnixNano(),
+			done:       make(chan struct{}),
+		}
+
+		for i := 1; i < coarseCheckInterval; i++ {
+			assert.That(testInstance.Now().Equal(stalled), is.True())
+		}
+
+		before := time.Now()
+		assert.That(testInstance.Now().Before(before), is.False())
+	})
+
+	t.Run("never goes backwards", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance := NewCoarseClockWithEpoch(0, time.Hour)
+		defer testInstance.Close()
+
+		now := time.Now().UnixNano()
+		testInstance.store(now + int64(time.Minute))
+		testInstance.store(now)
+		assert.That(testInstance.Now().UnixNano(), is.EqualTo(now+int64(time.Minute)))
+	})
+}
+
+func BenchmarkUnixClock_Seconds(b *testing.B) {
+	clock := NewUnixClockWithEpoch(0)
+	for i := 0; i < b.N; i++ {
+		clock.Seconds()
+	}
+}
+
+func BenchmarkCoarseClock_Seconds(b *testing.B) {
+	clock := NewCoarseClockWithEpoch(0, time.Millisecond)
+	defer clock.Close()
+	for i := 0; i < b.N; i++ {
+		clock.Seconds()
+	}
+}
+
+func BenchmarkUnixClock_Seconds_Parallel(b *testing.B) {
+	clock := NewUnixClockWithEpoch(0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			clock.Seconds()
+		}
+	})
+}
+
+func BenchmarkCoarseClock_Seconds_Parallel(b *testing.B) {
+	clock := NewCoarseClockWithEpoch(0, time.Millisecond)
+	defer clock.Close()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			clock.Seconds()
+		}
+	})
+}
@@ -1,12 +1,17 @@
 package internal
 
-import "math"
+import (
+	"math"
+	"time"
+)
 
 const (
 	totalBits    = 64
 	epochBits    = 42
 	nodeBits     = 8
 	sequenceBits = 14
+
+	defaultWait = 250 * time.Millisecond
 )
 
 var (
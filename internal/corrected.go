@@ -0,0 +1,143 @@
+package internal
+
+import (
+	"sync"
+	"time"
+)
+
+// OffsetSource provides the estimated offset of the local clock and the bound of its error
+type OffsetSource interface {
+	Offset() (offset, uncertainty time.Duration, err error)
+}
+
+// nowClock is implemented by clocks providing a sub-second reading, Now has to agree with Seconds
+type nowClock interface {
+	Clock
+	Now() time.Time
+}
+
+// correctedClockImpl adds the offset of a source to a base clock. Changes of the offset are slewed at rate
+// instead of stepped, and the returned seconds never move backwards
+type correctedClockImpl struct {
+	base    Clock
+	source  OffsetSource
+	rate    float64
+	refresh time.Duration
+	now     func() time.Time
+
+	lock        sync.Mutex
+	applied     time.Duration
+	target      time.Duration
+	uncertainty time.Duration
+	err         error
+	slewed      time.Time
+	refreshed   time.Time
+	refreshing  bool
+	last        uint64
+}
+
+// NewCorrectedClock returns a clock correcting base by the offset of source. The first offset is applied at once,
+// later ones are slewed by at most rate per elapsed second. The source is read at most every refresh
+func NewCorrectedClock(base Clock, source OffsetSource, rate float64, refresh time.Duration, now func() time.Time) Clock {
+	r := &correctedClockImpl{base: base, source: source, rate: rate, refresh: refresh, now: now}
+	current := now()
+	offset, uncertainty, err := source.Offset()
+	r.store(current, offset, uncertainty, err)
+	r.applied = r.target
+	r.slewed = current
+	return r
+}
+
+// Seconds returns the seconds of the base clock corrected by the applied offset
+func (c *correctedClockImpl) Seconds() uint64 {
+	c.update()
+	defer c.lock.Unlock()
+
+	seconds := c.corrected()
+	if seconds < 0 {
+		seconds = 0
+	}
+	if uint64(seconds) > c.last {
+		c.last = uint64(seconds)
+	}
+	return c.last
+}
+
+// corrected adds the applied offset to the base clock before truncating to whole seconds. Clocks without a
+// sub-second reading are taken at the start of their current second
+func (c *correctedClockImpl) corrected() int64 {
+	if n, ok := c.base.(nowClock); ok {
+		return n.Now().Add(c.applied).Unix() - int64(c.Epoch())
+	}
+	seconds := int64(c.base.Seconds()) + int64(c.applied/time.Second)
+	if c.applied%time.Second < 0 {
+		seconds--
+	}
+	return seconds
+}
+
+// Uncertainty returns the error bound of the source plus the part of the offset which has not been slewed yet
+func (c *correctedClockImpl) Uncertainty() (time.Duration, error) {
+	c.update()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return 0, c.err
+	}
+	pending := c.target - c.applied
+	if pending < 0 {
+		pending = -pending
+	}
+	return c.uncertainty + pending, nil
+}
+
+// Epoch returns the epoch of the base clock, the UNIX epoch if it has none
+func (c *correctedClockImpl) Epoch() uint64 {
+	if e, ok := c.base.(EpochClock); ok {
+		return e.Epoch()
+	}
+	return 0
+}
+
+// update acquires the lock, refreshes the offset if it is due and slews the applied offset. It returns with the
+// lock held. The source is read with the lock released, meanwhile other readers keep using the previous offset
+func (c *correctedClockImpl) update() {
+	now := c.now()
+	c.lock.Lock()
+	if !c.refreshing && now.Sub(c.refreshed) >= c.refresh {
+		c.refreshing = true
+		c.lock.Unlock()
+		offset, uncertainty, err := c.source.Offset()
+		c.lock.Lock()
+		c.refreshing = false
+		c.store(now, offset, uncertainty, err)
+	}
+
+	if !now.After(c.slewed) {
+		return
+	}
+	step := time.Duration(float64(now.Sub(c.slewed)) * c.rate)
+	c.slewed = now
+	if step <= 0 {
+		return
+	}
+
+	switch pending := c.target - c.applied; {
+	case pending > step:
+		c.applied += step
+	case pending < -step:
+		c.applied -= step
+	default:
+		c.applied = c.target
+	}
+}
+
+// store keeps the offset read from the source, on failure the previous offset stays the target
+func (c *correctedClockImpl) store(now time.Time, offset, uncertainty time.Duration, err error) {
+	c.refreshed = now
+	if c.err = err; err != nil {
+		return
+	}
+	c.target = offset
+	c.uncertainty = uncertainty
+}
@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fixedOffsetSource struct {
+	offset      time.Duration
+	uncertainty time.Duration
+	err         error
+}
+
+func (f *fixedOffsetSource) Offset() (time.Duration, time.Duration, error) {
+	return f.offset, f.uncertainty, f.err
+}
+
+// blockingOffsetSource blocks Offset until release is closed
+type blockingOffsetSource struct {
+	calls   int
+	lock    sync.Mutex
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingOffsetSource) Offset() (time.Duration, time.Duration, error) {
+	b.lock.Lock()
+	b.calls++
+	calls := b.calls
+	b.lock.Unlock()
+	if calls > 1 {
+		close(b.entered)
+		<-b.release
+	}
+	return time.Second, 0, nil
+}
+
+// secondsClock is a clock without a sub-second reading
+type secondsClock uint64
+
+func (s secondsClock) Seconds() uint64 { return uint64(s) }
+
+// fakeTime is a clock and a time source moving only when advanced
+type fakeTime struct {
+	now time.Time
+}
+
+func (f *fakeTime) Seconds() uint64         { return uint64(f.now.Unix()) }
+func (f *fakeTime) Now() time.Time          { return f.now }
+func (f *fakeTime) Advance(d time.Duration) { f.now = f.now.Add(d) }
+
+func TestCorrectedClock(t *testing.T) {
+	t.Run("first offset applied at once", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := &fakeTime{now: time.Unix(1000, 0)}
+		testInstance := NewCorrectedClock(clock, &fixedOffsetSource{offset: 5 * time.Second}, 0.5, 0, clock.Now)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1005)))
+	})
+
+	t.Run("slews forward", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := &fakeTime{now: time.Unix(1000, 0)}
+		source := &fixedOffsetSource{}
+		testInstance := NewCorrectedClock(clock, source, 0.5, 0, clock.Now)
+
+		source.offset = 4 * time.Second
+		clock.Advance(2 * time.Second)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1003)))
+
+		clock.Advance(2 * time.Second)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1006)))
+
+		clock.Advance(10 * time.Second)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1018)))
+	})
+
+	t.Run("slews backwards without moving backwards", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := &fakeTime{now: time.Unix(1000, 0)}
+		source := &fixedOffsetSource{}
+		testInstance := NewCorrectedClock(clock, source, 0.5, 0, clock.Now)
+
+		source.offset = -10 * time.Second
+		last := testInstance.Seconds()
+		for i := 0; i < 40; i++ {
+			clock.Advance(time.Second)
+			r := testInstance.Seconds()
+			assert.That(r >= last, is.True())
+			last = r
+		}
+		assert.That(last, is.EqualTo(uint64(1030)))
+	})
+
+	t.Run("uncertainty includes pending offset", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := &fakeTime{now: time.Unix(1000, 0)}
+		source := &fixedOffsetSource{uncertainty: time.Millisecond}
+		testInstance := NewCorrectedClock(clock, source, 0.5, 0, clock.Now).(*correctedClockImpl)
+
+		r, err := testInstance.Uncertainty()
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(time.Millisecond))
+
+		source.offset = time.Second
+		clock.Advance(time.Second)
+		r, _ = testInstance.Uncertainty()
+		assert.That(r, is.EqualTo(time.Millisecond+500*time.Millisecond))
+
+		source.err = errors.New("agent down")
+		clock.Advance(time.Second)
+		_, err = testInstance.Uncertainty()
+		assert.That(err, is.EqualTo(source.err))
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := &fakeTime{now: time.Unix(1000, 0)}
+		source := &fixedOffsetSource{}
+		testInstance := NewCorrectedClock(clock, source, 0.5, time.Minute, clock.Now)
+
+		source.offset = 30 * time.Second
+		clock.Advance(30 * time.Second)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1030)))
+
+		clock.Advance(30 * time.Second)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1075)))
+
+		clock.Advance(30 * time.Second)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1120)))
+	})
+
+	t.Run("offset added before truncating", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := &fakeTime{now: time.Unix(1000, 300*int64(time.Millisecond))}
+		testInstance := NewCorrectedClock(clock, &fixedOffsetSource{offset: 600 * time.Millisecond}, 0.5, 0, clock.Now)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1000)))
+
+		clock.Advance(200 * time.Millisecond)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1001)))
+
+		clock = &fakeTime{now: time.Unix(1000, 700*int64(time.Millisecond))}
+		testInstance = NewCorrectedClock(clock, &fixedOffsetSource{offset: 400 * time.Millisecond}, 0.5, 0, clock.Now)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1001)))
+	})
+
+	t.Run("clock without sub-second reading", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance := NewCorrectedClock(secondsClock(1000), &fixedOffsetSource{offset: 600 * time.Millisecond}, 0.5, 0, time.Now)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1000)))
+
+		testInstance = NewCorrectedClock(secondsClock(1000), &fixedOffsetSource{offset: -600 * time.Millisecond}, 0.5, 0, time.Now)
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(999)))
+	})
+
+	t.Run("refresh does not block readers", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := &fakeTime{now: time.Unix(1000, 0)}
+		source := &blockingOffsetSource{entered: make(chan struct{}), release: make(chan struct{})}
+		testInstance := NewCorrectedClock(clock, source, 0.5, 0, func() time.Time { return time.Unix(1000, 0) })
+
+		refreshed := make(chan uint64)
+		go func() { refreshed <- testInstance.Seconds() }()
+		<-source.entered
+
+		assert.That(testInstance.Seconds(), is.EqualTo(uint64(1001)))
+		close(source.release)
+		assert.That(<-refreshed, is.EqualTo(uint64(1001)))
+	})
+
+	t.Run("epoch of base", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance := NewCorrectedClock(NewUnixClockWithEpoch(100), &fixedOffsetSource{}, 1, 0, time.Now)
+		assert.That(testInstance.(EpochClock).Epoch(), is.EqualTo(uint64(100)))
+	})
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"strings"
+)
+
+const (
+	base32Alphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// FormatBase32 formats id using the lower case Crockford alphabet
+func FormatBase32(id uint64) string {
+	return format(id, base32Alphabet)
+}
+
+// ParseBase32 parses a Crockford base32 value, case insensitive
+func ParseBase32(s string) (uint64, error) {
+	return parse(strings.ToLower(s), base32Alphabet)
+}
+
+// FormatBase62 formats id using digits, upper and lower case letters
+func FormatBase62(id uint64) string {
+	return format(id, base62Alphabet)
+}
+
+// ParseBase62 parses a base62 value
+func ParseBase62(s string) (uint64, error) {
+	return parse(s, base62Alphabet)
+}
+
+func format(id uint64, alphabet string) string {
+	if id == 0 {
+		return alphabet[:1]
+	}
+
+	base := uint64(len(alphabet))
+	var buf [64]byte
+	i := len(buf)
+	for id > 0 {
+		i--
+		buf[i] = alphabet[id%base]
+		id /= base
+	}
+	return string(buf[i:])
+}
+
+func parse(s string, alphabet string) (uint64, error) {
+	if s == "" {
+		return 0, ErrInvalidEncoding
+	}
+
+	base := uint64(len(alphabet))
+	var r uint64
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(alphabet, s[i])
+		if digit < 0 {
+			return 0, ErrInvalidEncoding
+		}
+		if r > (^uint64(0)-uint64(digit))/base {
+			return 0, ErrInvalidEncoding
+		}
+		r = r*base + uint64(digit)
+	}
+	return r, nil
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"math"
+	"testing"
+)
+
+func TestBase32(t *testing.T) {
+	t.Run("format", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(FormatBase32(0), is.EqualTo("0"))
+		assert.That(FormatBase32(31), is.EqualTo("z"))
+		assert.That(FormatBase32(32), is.EqualTo("10"))
+		assert.That(FormatBase32(math.MaxUint64), is.EqualTo("fzzzzzzzzzzzz"))
+	})
+	t.Run("parse", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := ParseBase32("FZZZZZZZZZZZZ")
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(uint64(math.MaxUint64)))
+	})
+	t.Run("parse invalid character", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := ParseBase32("u")
+		assert.That(err, is.EqualTo(ErrInvalidEncoding))
+	})
+	t.Run("parse overflow", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := ParseBase32("g000000000000")
+		assert.That(err, is.EqualTo(ErrInvalidEncoding))
+	})
+}
+
+func TestBase62(t *testing.T) {
+	t.Run("format", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(FormatBase62(0), is.EqualTo("0"))
+		assert.That(FormatBase62(61), is.EqualTo("z"))
+		assert.That(FormatBase62(62), is.EqualTo("10"))
+		assert.That(FormatBase62(math.MaxUint64), is.EqualTo("LygHa16AHYF"))
+	})
+	t.Run("round trip", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		for _, id := range []uint64{1, 6910615572447233, math.MaxUint64} {
+			r, err := ParseBase62(FormatBase62(id))
+			assert.That(err, is.Nil())
+			assert.That(r, is.EqualTo(id))
+		}
+	})
+	t.Run("parse empty", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := ParseBase62("")
+		assert.That(err, is.EqualTo(ErrInvalidEncoding))
+	})
+	t.Run("parse overflow", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := ParseBase62("LygHa16AHYG")
+		assert.That(err, is.EqualTo(ErrInvalidEncoding))
+	})
+}
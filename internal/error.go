@@ -2,10 +2,21 @@ package internal
 
 import (
 	"errors"
-	"fmt"
 )
 
 var (
 	ErrClockNotMonotonic     = errors.New("clock is not monotonic")
-	ErrMaxSequenceOutOfRange = errors.New(fmt.Sprintf("maxSequence is capped to %d", MaxSequence))
+	ErrMaxSequenceOutOfRange = errors.New("maxSequence exceeds the sequence bits of the layout")
+	ErrLayoutInvalid         = errors.New("layout must use 64 bits with at most 8 node bits and 1 to 16 sequence bits")
+	ErrNodeIDOutOfRange      = errors.New("nodeID does not fit into the node bits of the layout")
+	ErrInvalidEncoding       = errors.New("value is not valid in the requested encoding")
+	ErrSequenceOutOfRange    = errors.New("sequence does not fit into the sequence bits of the layout")
+	ErrTimestampOverflow     = errors.New("timestamp does not fit into the timestamp bits of the layout")
+	ErrEpochInFuture         = errors.New("epoch is in the future")
+	ErrFieldsInvalid         = errors.New("fields must have unique non empty names and use exactly the node bits of the layout")
+	ErrFieldValueOutOfRange  = errors.New("field value does not fit into the bits of the field")
+	ErrNodeIDsInvalid        = errors.New("node ids must not be empty and must not contain duplicates")
+	ErrSequenceExhausted     = errors.New("sequence is exhausted for the current tick")
+	ErrReservationUsed       = errors.New("reservation was already used or cancelled")
+	ErrVariantInvalid        = errors.New("variant must use at most 64 bits with at most 16 node bits and 1 to 16 sequence bits")
 )
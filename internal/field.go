@@ -0,0 +1,54 @@
+package internal
+
+// Field is a named part of the node bits, fields are stored from most to least significant
+type Field struct {
+	Name string
+	Bits uint8
+}
+
+// MaxValue returns the largest value which can be stored in the field
+func (f Field) MaxValue() uint8 {
+	return uint8(mask(f.Bits))
+}
+
+// ValidateFields checks that the names are unique and not empty and that the fields use exactly nodeBits
+func ValidateFields(fields []Field, nodeBits uint8) error {
+	names := make(map[string]struct{}, len(fields))
+	total := 0
+	for _, field := range fields {
+		if _, ok := names[field.Name]; ok || field.Name == "" || field.Bits == 0 {
+			return ErrFieldsInvalid
+		}
+		names[field.Name] = struct{}{}
+		total += int(field.Bits)
+	}
+
+	if total != int(nodeBits) {
+		return ErrFieldsInvalid
+	}
+	return nil
+}
+
+// ComposeNodeID packs the values of fields into a node id, values[i] belongs to fields[i]
+func ComposeNodeID(fields []Field, values []uint8) (uint8, error) {
+	var r uint64
+	for i, field := range fields {
+		if values[i] > field.MaxValue() {
+			return 0, ErrFieldValueOutOfRange
+		}
+		r = r<<field.Bits | uint64(values[i])
+	}
+	return uint8(r), nil
+}
+
+// FieldValue returns the value of the field name within nodeID, false if there is no such field
+func FieldValue(fields []Field, name string, nodeID uint8) (uint8, bool) {
+	shift := uint8(0)
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Name == name {
+			return uint8((uint64(nodeID) >> shift) & mask(fields[i].Bits)), true
+		}
+		shift += fields[i].Bits
+	}
+	return 0, false
+}
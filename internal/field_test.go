@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"testing"
+)
+
+func TestValidateFields(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(ValidateFields([]Field{{"region", 3}, {"worker", 5}}, 8), is.Nil())
+	})
+	t.Run("no fields", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(ValidateFields(nil, 0), is.Nil())
+	})
+	t.Run("less than node bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(ValidateFields([]Field{{"region", 3}, {"worker", 4}}, 8), is.EqualTo(ErrFieldsInvalid))
+	})
+	t.Run("more than node bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(ValidateFields([]Field{{"region", 3}, {"worker", 6}}, 8), is.EqualTo(ErrFieldsInvalid))
+	})
+	t.Run("duplicated name", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(ValidateFields([]Field{{"worker", 3}, {"worker", 5}}, 8), is.EqualTo(ErrFieldsInvalid))
+	})
+	t.Run("empty name", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(ValidateFields([]Field{{"", 3}, {"worker", 5}}, 8), is.EqualTo(ErrFieldsInvalid))
+	})
+	t.Run("no bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(ValidateFields([]Field{{"type", 0}, {"worker", 8}}, 8), is.EqualTo(ErrFieldsInvalid))
+	})
+}
+
+func TestComposeNodeID(t *testing.T) {
+	fields := []Field{{"type", 2}, {"region", 3}, {"worker", 3}}
+
+	t.Run("ok", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := ComposeNodeID(fields, []uint8{2, 5, 1})
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(uint8(0b10_101_001)))
+	})
+	t.Run("value out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := ComposeNodeID(fields, []uint8{4, 0, 0})
+		assert.That(err, is.EqualTo(ErrFieldValueOutOfRange))
+	})
+}
+
+func TestFieldValue(t *testing.T) {
+	fields := []Field{{"type", 2}, {"region", 3}, {"worker", 3}}
+
+	for name, expected := range map[string]uint8{"type": 2, "region": 5, "worker": 1} {
+		t.Run(name, func(t *testing.T) {
+			assert := hamcrest.NewAssertion(t)
+			r, ok := FieldValue(fields, name, 0b10_101_001)
+			assert.That(ok, is.True())
+			assert.That(r, is.EqualTo(expected))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, ok := FieldValue(fields, "datacenter", 0b10_101_001)
+		assert.That(ok, is.False())
+	})
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"sync"
+)
+
+// hlcSequenceProviderImpl is a hybrid logical clock, its timestamp is the max of the clock and all observed
+// timestamps and the iteration is the logical counter. It never goes backwards, once the iterations of a tick are
+// exhausted it moves on to the next tick ahead of the clock
+type hlcSequenceProviderImpl struct {
+	clock        Clock
+	maxIteration uint16
+	nodeID       uint8
+	lock         sync.Mutex
+
+	currentSeconds   uint64
+	currentIteration uint16
+}
+
+func (h *hlcSequenceProviderImpl) Sequence() Sequence {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if seconds := h.clock.Seconds(); seconds > h.currentSeconds {
+		h.currentSeconds = seconds
+		h.currentIteration = 0
+	}
+
+	if h.currentIteration >= h.maxIteration {
+		h.currentSeconds++
+		h.currentIteration = 0
+	}
+
+	h.currentIteration++
+	return sequenceOk(h.currentSeconds, h.currentIteration)
+}
+
+// Observe advances the logical time so the next sequence results in an ID greater than the remote ID,
+// the node decides whether the remote tick can be shared or the next tick has to be used
+func (h *hlcSequenceProviderImpl) Observe(seconds uint64, nodeID uint8, iteration uint16) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	switch {
+	case nodeID > h.nodeID:
+		seconds, iteration = seconds+1, 0
+	case nodeID < h.nodeID:
+		iteration = 0
+	}
+
+	if seconds > h.currentSeconds || seconds == h.currentSeconds && iteration > h.currentIteration {
+		h.currentSeconds = seconds
+		h.currentIteration = iteration
+	}
+}
+
+// NewHLCSequenceProvider returns a hybrid logical clock issuing sequences for the node nodeID
+func NewHLCSequenceProvider(clock Clock, maxSequence uint16, nodeID uint8, layout Layout) (*hlcSequenceProviderImpl, error) {
+	if maxSequence > layout.MaxSequence() {
+		return nil, ErrMaxSequenceOutOfRange
+	}
+	if nodeID > layout.MaxNodeID() {
+		return nil, ErrNodeIDOutOfRange
+	}
+
+	return &hlcSequenceProviderImpl{
+		clock:        clock,
+		maxIteration: maxSequence,
+		nodeID:       nodeID,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package internal_test
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake/internal"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"testing"
+)
+
+func sequenceOk(seconds uint64, it uint16) internal.Sequence {
+	return internal.Sequence{Seconds: seconds, Iteration: it}
+}
+
+func TestNewHLCSequenceProvider(t *testing.T) {
+	t.Run("max sequence out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := internal.NewHLCSequenceProvider(snowflaketest.NewManualClock(10), 16384, 1, internal.DefaultLayout)
+		assert.That(err, is.EqualTo(internal.ErrMaxSequenceOutOfRange))
+	})
+	t.Run("node out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := internal.NewHLCSequenceProvider(snowflaketest.NewManualClock(10), 10, 16, internal.Layout{TimestampBits: 44, NodeBits: 4, SequenceBits: 16})
+		assert.That(err, is.EqualTo(internal.ErrNodeIDOutOfRange))
+	})
+}
+
+func TestHLCSequenceProvider_Sequence(t *testing.T) {
+	t.Run("follows the clock", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflaketest.NewManualClock(10)
+		testInstance, _ := internal.NewHLCSequenceProvider(clock, 2, 1, internal.DefaultLayout)
+		assert.That(testInstance.Sequence(), is.EqualTo(sequenceOk(10, 1)))
+		assert.That(testInstance.Sequence(), is.EqualTo(sequenceOk(10, 2)))
+
+		clock.Set(12)
+		assert.That(testInstance.Sequence(), is.EqualTo(sequenceOk(12, 1)))
+	})
+
+	t.Run("moves ahead once exhausted", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance, _ := internal.NewHLCSequenceProvider(snowflaketest.NewManualClock(10), 2, 1, internal.DefaultLayout)
+		testInstance.Sequence()
+		testInstance.Sequence()
+		assert.That(testInstance.Sequence(), is.EqualTo(sequenceOk(11, 1)))
+	})
+
+	t.Run("ignores clock rollback", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflaketest.NewManualClock(10)
+		testInstance, _ := internal.NewHLCSequenceProvider(clock, 2, 1, internal.DefaultLayout)
+		testInstance.Sequence()
+
+		clock.Set(5)
+		assert.That(testInstance.Sequence(), is.EqualTo(sequenceOk(10, 2)))
+	})
+}
+
+func TestHLCSequenceProvider_Observe(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		nodeID   uint8
+		expected internal.Sequence
+	}{
+		{"same node", 5, sequenceOk(20, 8)},
+		{"lower node", 4, sequenceOk(20, 1)},
+		{"higher node", 6, sequenceOk(21, 1)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := hamcrest.NewAssertion(t)
+
+			testInstance, _ := internal.NewHLCSequenceProvider(snowflaketest.NewManualClock(10), 100, 5, internal.DefaultLayout)
+			testInstance.Observe(20, tc.nodeID, 7)
+			assert.That(testInstance.Sequence(), is.EqualTo(tc.expected))
+		})
+	}
+
+	t.Run("observing the past", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance, _ := internal.NewHLCSequenceProvider(snowflaketest.NewManualClock(10), 100, 5, internal.DefaultLayout)
+		testInstance.Sequence()
+		testInstance.Observe(9, 6, 50)
+		assert.That(testInstance.Sequence(), is.EqualTo(sequenceOk(10, 2)))
+	})
+
+	t.Run("exhausted remote iteration", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance, _ := internal.NewHLCSequenceProvider(snowflaketest.NewManualClock(10), 100, 5, internal.DefaultLayout)
+		testInstance.Observe(20, 5, 100)
+		assert.That(testInstance.Sequence(), is.EqualTo(sequenceOk(21, 1)))
+	})
+}
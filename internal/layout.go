@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"math"
+	"time"
+)
+
+// Layout describes how the 64 bits of an ID are split between timestamp, node and sequence
+// The fields are stored from most to least significant: |--Timestamp--|--Node--|--Sequence--|
+type Layout struct {
+	TimestampBits uint8
+	NodeBits      uint8
+	SequenceBits  uint8
+}
+
+var (
+	DefaultLayout = Layout{TimestampBits: epochBits, NodeBits: nodeBits, SequenceBits: sequenceBits}
+)
+
+// Validate checks that the layout uses exactly 64 bits and that node and sequence fit into their go types
+func (l Layout) Validate() error {
+	if l.TimestampBits == 0 || l.SequenceBits == 0 {
+		return ErrLayoutInvalid
+	}
+	if l.NodeBits > 8 || l.SequenceBits > 16 {
+		return ErrLayoutInvalid
+	}
+	if int(l.TimestampBits)+int(l.NodeBits)+int(l.SequenceBits) != totalBits {
+		return ErrLayoutInvalid
+	}
+	return nil
+}
+
+// MaxTimestamp returns the largest timestamp which can be stored
+func (l Layout) MaxTimestamp() uint64 {
+	return mask(l.TimestampBits)
+}
+
+// MaxNodeID returns the largest node id which can be stored
+func (l Layout) MaxNodeID() uint8 {
+	return uint8(mask(l.NodeBits))
+}
+
+// MaxSequence returns the largest sequence which can be stored
+func (l Layout) MaxSequence() uint16 {
+	return uint16(mask(l.SequenceBits))
+}
+
+// Horizon returns the last second which can be stored by the layout when counting from epoch
+func (l Layout) Horizon(epoch uint64) time.Time {
+	if epoch > math.MaxInt64 || l.MaxTimestamp() > math.MaxInt64-epoch {
+		return time.Unix(math.MaxInt64, 0).UTC()
+	}
+	return time.Unix(int64(epoch+l.MaxTimestamp()), 0).UTC()
+}
+
+// Encode packs the components into an ID, components exceeding their width get truncated
+func (l Layout) Encode(timestamp uint64, nodeID uint8, sequence uint16) uint64 {
+	id := (timestamp & l.MaxTimestamp()) << (l.NodeBits + l.SequenceBits)
+	id |= (uint64(nodeID) & mask(l.NodeBits)) << l.SequenceBits
+	id |= uint64(sequence) & mask(l.SequenceBits)
+	return id
+}
+
+// Pack is Encode but rejects components exceeding their width instead of truncating them
+func (l Layout) Pack(timestamp uint64, nodeID uint8, sequence uint16) (uint64, error) {
+	if timestamp > l.MaxTimestamp() {
+		return 0, ErrTimestampOverflow
+	}
+	if nodeID > l.MaxNodeID() {
+		return 0, ErrNodeIDOutOfRange
+	}
+	if sequence > l.MaxSequence() {
+		return 0, ErrSequenceOutOfRange
+	}
+	return l.Encode(timestamp, nodeID, sequence), nil
+}
+
+// Decode unpacks an ID into its components
+func (l Layout) Decode(id uint64) (timestamp uint64, nodeID uint8, sequence uint16) {
+	timestamp = (id >> (l.NodeBits + l.SequenceBits)) & l.MaxTimestamp()
+	nodeID = uint8((id >> l.SequenceBits) & mask(l.NodeBits))
+	sequence = uint16(id & mask(l.SequenceBits))
+	return
+}
+
+func mask(bits uint8) uint64 {
+	if bits >= totalBits {
+		return ^uint64(0)
+	}
+	return (uint64(1) << bits) - 1
+}
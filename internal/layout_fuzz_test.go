@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+// fuzzLayout derives a valid layout from arbitrary bit counts
+func fuzzLayout(nodeBits, sequenceBits uint8) Layout {
+	r := Layout{NodeBits: nodeBits % 9, SequenceBits: sequenceBits%16 + 1}
+	r.TimestampBits = totalBits - r.NodeBits - r.SequenceBits
+	return r
+}
+
+// compareComponents orders by timestamp, then node and then sequence
+func compareComponents(t1 uint64, n1 uint8, s1 uint16, t2 uint64, n2 uint8, s2 uint16) int {
+	switch {
+	case t1 != t2:
+		return compare(t1 < t2)
+	case n1 != n2:
+		return compare(n1 < n2)
+	case s1 != s2:
+		return compare(s1 < s2)
+	}
+	return 0
+}
+
+func compare(less bool) int {
+	if less {
+		return -1
+	}
+	return 1
+}
+
+func compareIDs(a, b uint64) int {
+	if a == b {
+		return 0
+	}
+	return compare(a < b)
+}
+
+func FuzzLayout_RoundTrip(f *testing.F) {
+	f.Add(uint64(1647619145), uint8(128), uint16(1), uint8(8), uint8(13))
+	f.Add(uint64(0), uint8(0), uint16(0), uint8(0), uint8(15))
+	f.Add(^uint64(0), ^uint8(0), ^uint16(0), uint8(8), uint8(15))
+
+	f.Fuzz(func(t *testing.T, timestamp uint64, nodeID uint8, sequence uint16, nodeBits, sequenceBits uint8) {
+		layout := fuzzLayout(nodeBits, sequenceBits)
+
+		id, err := layout.Pack(timestamp, nodeID, sequence)
+		fits := timestamp <= layout.MaxTimestamp() && nodeID <= layout.MaxNodeID() && sequence <= layout.MaxSequence()
+		if !fits {
+			if err == nil {
+				t.Fatalf("layout %v accepted out of range components %d %d %d", layout, timestamp, nodeID, sequence)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("layout %v rejected components %d %d %d: %v", layout, timestamp, nodeID, sequence, err)
+		}
+
+		rt, rn, rs := layout.Decode(id)
+		if rt != timestamp || rn != nodeID || rs != sequence {
+			t.Fatalf("layout %v decoded %d %d %d, expected %d %d %d", layout, rt, rn, rs, timestamp, nodeID, sequence)
+		}
+		if layout.Encode(rt, rn, rs) != id {
+			t.Fatalf("layout %v encoded %d differently after decoding", layout, id)
+		}
+	})
+}
+
+func FuzzLayout_Order(f *testing.F) {
+	f.Add(uint64(10), uint8(1), uint16(2), uint64(10), uint8(2), uint16(1), uint8(8), uint8(13))
+	f.Add(uint64(10), uint8(255), uint16(16383), uint64(11), uint8(0), uint16(1), uint8(8), uint8(13))
+
+	f.Fuzz(func(t *testing.T, t1 uint64, n1 uint8, s1 uint16, t2 uint64, n2 uint8, s2 uint16, nodeBits, sequenceBits uint8) {
+		layout := fuzzLayout(nodeBits, sequenceBits)
+
+		t1, n1, s1 = layout.Decode(layout.Encode(t1, n1, s1))
+		t2, n2, s2 = layout.Decode(layout.Encode(t2, n2, s2))
+
+		expected := compareComponents(t1, n1, s1, t2, n2, s2)
+		if r := compareIDs(layout.Encode(t1, n1, s1), layout.Encode(t2, n2, s2)); r != expected {
+			t.Fatalf("layout %v orders (%d %d %d) and (%d %d %d) as %d, expected %d", layout, t1, n1, s1, t2, n2, s2, r, expected)
+		}
+	})
+}
+
+func TestLayout_Properties(t *testing.T) {
+	config := &quick.Config{MaxCount: 10000, Rand: rand.New(rand.NewSource(42))}
+
+	t.Run("fuzz layouts are valid", func(t *testing.T) {
+		property := func(nodeBits, sequenceBits uint8) bool {
+			return fuzzLayout(nodeBits, sequenceBits).Validate() == nil
+		}
+		if err := quick.Check(property, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		property := func(timestamp uint64, nodeID uint8, sequence uint16, nodeBits, sequenceBits uint8) bool {
+			layout := fuzzLayout(nodeBits, sequenceBits)
+			timestamp, nodeID, sequence = timestamp&layout.MaxTimestamp(), nodeID&layout.MaxNodeID(), sequence&layout.MaxSequence()
+
+			rt, rn, rs := layout.Decode(layout.Encode(timestamp, nodeID, sequence))
+			return rt == timestamp && rn == nodeID && rs == sequence
+		}
+		if err := quick.Check(property, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("order", func(t *testing.T) {
+		property := func(t1 uint64, n1 uint8, s1 uint16, t2 uint64, n2 uint8, s2 uint16, nodeBits, sequenceBits uint8) bool {
+			layout := fuzzLayout(nodeBits, sequenceBits)
+			t1, n1, s1 = layout.Decode(layout.Encode(t1, n1, s1))
+			t2, n2, s2 = layout.Decode(layout.Encode(t2, n2, s2))
+			return compareIDs(layout.Encode(t1, n1, s1), layout.Encode(t2, n2, s2)) == compareComponents(t1, n1, s1, t2, n2, s2)
+		}
+		if err := quick.Check(property, config); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("rejects out of range", func(t *testing.T) {
+		property := func(timestamp uint64, nodeID uint8, sequence uint16, nodeBits, sequenceBits uint8) bool {
+			layout := fuzzLayout(nodeBits, sequenceBits)
+			_, err := layout.Pack(timestamp, nodeID, sequence)
+			fits := timestamp <= layout.MaxTimestamp() && nodeID <= layout.MaxNodeID() && sequence <= layout.MaxSequence()
+			return fits == (err == nil)
+		}
+		if err := quick.Check(property, config); err != nil {
+			t.Error(err)
+		}
+	})
+}
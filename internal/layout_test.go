@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"math"
+	"testing"
+)
+
+func TestDefaultLayout(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	assert.That(DefaultLayout.TimestampBits, is.EqualTo(uint8(42)))
+	assert.That(DefaultLayout.NodeBits, is.EqualTo(uint8(8)))
+	assert.That(DefaultLayout.SequenceBits, is.EqualTo(uint8(14)))
+	assert.That(DefaultLayout.Validate(), is.Nil())
+}
+
+func TestLayout_Validate(t *testing.T) {
+	t.Run("less than 64 bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Layout{41, 8, 14}.Validate(), is.EqualTo(ErrLayoutInvalid))
+	})
+	t.Run("more than 64 bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Layout{43, 8, 14}.Validate(), is.EqualTo(ErrLayoutInvalid))
+	})
+	t.Run("node bits exceed uint8", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Layout{40, 10, 14}.Validate(), is.EqualTo(ErrLayoutInvalid))
+	})
+	t.Run("sequence bits exceed uint16", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Layout{40, 7, 17}.Validate(), is.EqualTo(ErrLayoutInvalid))
+	})
+	t.Run("no sequence bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Layout{56, 8, 0}.Validate(), is.EqualTo(ErrLayoutInvalid))
+	})
+	t.Run("no node bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Layout{48, 0, 16}.Validate(), is.Nil())
+	})
+}
+
+func TestLayout_Max(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	testInstance := Layout{41, 7, 16}
+	assert.That(testInstance.MaxTimestamp(), is.EqualTo(uint64(2199023255551)))
+	assert.That(testInstance.MaxNodeID(), is.EqualTo(uint8(127)))
+	assert.That(testInstance.MaxSequence(), is.EqualTo(uint16(65535)))
+}
+
+func TestLayout_Encode(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r := DefaultLayout.Encode(10, 42, 1)
+		assert.That(fmt.Sprintf("%064b", r), is.EqualTo("0000000000000000000000000000000000000010100010101000000000000001"))
+	})
+	t.Run("custom", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r := Layout{52, 4, 8}.Encode(10, 3, 1)
+		assert.That(fmt.Sprintf("%064b", r), is.EqualTo("0000000000000000000000000000000000000000000000001010001100000001"))
+	})
+	t.Run("truncates", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r := Layout{52, 4, 8}.Encode(0, 255, 511)
+		assert.That(fmt.Sprintf("%064b", r), is.EqualTo("0000000000000000000000000000000000000000000000000000111111111111"))
+	})
+}
+
+func TestLayout_Decode(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	for _, layout := range []Layout{DefaultLayout, {52, 4, 8}, {48, 0, 16}} {
+		timestamp, nodeID, sequence := layout.Decode(layout.Encode(1647619145, layout.MaxNodeID(), 3))
+		assert.That(timestamp, is.EqualTo(uint64(1647619145)))
+		assert.That(nodeID, is.EqualTo(layout.MaxNodeID()))
+		assert.That(sequence, is.EqualTo(uint16(3)))
+	}
+}
+
+func TestLayout_Pack(t *testing.T) {
+	testInstance := Layout{52, 4, 8}
+
+	t.Run("ok", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := testInstance.Pack(10, 3, 1)
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(testInstance.Encode(10, 3, 1)))
+	})
+	t.Run("timestamp overflow", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := testInstance.Pack(1<<52, 3, 1)
+		assert.That(err, is.EqualTo(ErrTimestampOverflow))
+	})
+	t.Run("node id out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := testInstance.Pack(10, 16, 1)
+		assert.That(err, is.EqualTo(ErrNodeIDOutOfRange))
+	})
+	t.Run("sequence out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := testInstance.Pack(10, 3, 256)
+		assert.That(err, is.EqualTo(ErrSequenceOutOfRange))
+	})
+}
+
+func TestLayout_Horizon(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r := DefaultLayout.Horizon(0)
+		assert.That(r.Unix(), is.EqualTo(int64(4398046511103)))
+	})
+	t.Run("custom epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r := Layout{40, 8, 16}.Horizon(1288834974)
+		assert.That(r.Unix(), is.EqualTo(int64(1288834974+1<<40-1)))
+	})
+	t.Run("saturates", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r := Layout{63, 0, 1}.Horizon(1 << 62)
+		assert.That(r.Unix(), is.EqualTo(int64(math.MaxInt64)))
+	})
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"time"
+)
+
+// nodeSequence is the sequence space of a single node
+type nodeSequence struct {
+	nodeID   uint8
+	provider *sequenceProviderImpl
+}
+
+type multiNodeGeneratorImpl struct {
+	// nodes holds the primary nodes followed by the spare nodes
+	nodes     []nodeSequence
+	primaries int
+	layout    Layout
+	wait      time.Duration
+}
+
+// Next issues the ID of the first node which has sequences left in the current tick, spare nodes are only tried
+// while the clock is behind the last tick of a node. It waits for the next tick if a node is exhausted and fails with
+// ErrClockNotMonotonic if all nodes are behind the clock
+func (m *multiNodeGeneratorImpl) Next() (uint64, error) {
+	for {
+		var exhausted, rolledBack bool
+		for i, node := range m.nodes {
+			if i == m.primaries && !rolledBack {
+				break
+			}
+
+			seq := node.provider.TrySequence()
+			switch seq.Error {
+			case nil:
+				return m.layout.Pack(seq.Seconds, node.nodeID, seq.Iteration)
+			case ErrSequenceExhausted:
+				exhausted = true
+			case ErrClockNotMonotonic:
+				rolledBack = true
+			default:
+				return 0, seq.Error
+			}
+		}
+
+		if !exhausted {
+			return 0, ErrClockNotMonotonic
+		}
+		time.Sleep(m.wait)
+	}
+}
+
+// SetStart randomizes the first iteration of every tick of every node, see sequenceProviderImpl.SetStart
+func (m *multiNodeGeneratorImpl) SetStart(start func(n uint16) uint16) {
+	for _, node := range m.nodes {
+		node.provider.SetStart(start)
+	}
+}
+
+// NewMultiNodeGenerator returns a generator owning several node ids, every node has its own sequence space of
+// maxSequence IDs per tick which is used once the sequence space of the previous node is exhausted.
+// The spare nodes take over while the clock is behind the last tick of the nodes
+func NewMultiNodeGenerator(clock Clock, maxSequence uint16, nodeIDs []uint8, spareIDs []uint8, layout Layout) (*multiNodeGeneratorImpl, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	if len(nodeIDs) == 0 {
+		return nil, ErrNodeIDsInvalid
+	}
+	if maxSequence > layout.MaxSequence() {
+		return nil, ErrMaxSequenceOutOfRange
+	}
+
+	r := &multiNodeGeneratorImpl{primaries: len(nodeIDs), layout: layout, wait: defaultWait}
+	seen := make(map[uint8]struct{}, len(nodeIDs)+len(spareIDs))
+	for _, nodeID := range append(append([]uint8(nil), nodeIDs...), spareIDs...) {
+		if nodeID > layout.MaxNodeID() {
+			return nil, ErrNodeIDOutOfRange
+		}
+		if _, ok := seen[nodeID]; ok {
+			return nil, ErrNodeIDsInvalid
+		}
+		seen[nodeID] = struct{}{}
+
+		r.nodes = append(r.nodes, nodeSequence{
+			nodeID:   nodeID,
+			provider: NewTickSequenceProvider(clock, maxSequence, defaultWait),
+		})
+	}
+	return r, nil
+}
@@ -0,0 +1,141 @@
+package internal_test
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake/internal"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"testing"
+	"time"
+)
+
+func TestNewMultiNodeGenerator(t *testing.T) {
+	t.Run("no node ids", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := internal.NewMultiNodeGenerator(snowflaketest.NewManualClock(10), 2, nil, nil, internal.DefaultLayout)
+		assert.That(err, is.EqualTo(internal.ErrNodeIDsInvalid))
+	})
+	t.Run("duplicated node id", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := internal.NewMultiNodeGenerator(snowflaketest.NewManualClock(10), 2, []uint8{3, 3}, nil, internal.DefaultLayout)
+		assert.That(err, is.EqualTo(internal.ErrNodeIDsInvalid))
+	})
+	t.Run("node id out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := internal.NewMultiNodeGenerator(snowflaketest.NewManualClock(10), 2, []uint8{3, 16}, nil, internal.Layout{TimestampBits: 44, NodeBits: 4, SequenceBits: 16})
+		assert.That(err, is.EqualTo(internal.ErrNodeIDOutOfRange))
+	})
+	t.Run("max sequence out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := internal.NewMultiNodeGenerator(snowflaketest.NewManualClock(10), 16384, []uint8{3}, nil, internal.DefaultLayout)
+		assert.That(err, is.EqualTo(internal.ErrMaxSequenceOutOfRange))
+	})
+}
+
+func TestMultiNodeGenerator_Next(t *testing.T) {
+	t.Run("spills over to the next node", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflaketest.NewManualClock(10)
+		testInstance, err := internal.NewMultiNodeGenerator(clock, 2, []uint8{3, 5}, nil, internal.DefaultLayout)
+		assert.That(err, is.Nil())
+
+		for _, expected := range []uint64{
+			internal.DefaultLayout.Encode(10, 3, 1),
+			internal.DefaultLayout.Encode(10, 3, 2),
+			internal.DefaultLayout.Encode(10, 5, 1),
+			internal.DefaultLayout.Encode(10, 5, 2),
+		} {
+			r, err := testInstance.Next()
+			assert.That(err, is.Nil())
+			assert.That(r, is.EqualTo(expected))
+		}
+
+		clock.Set(11)
+		r, err := testInstance.Next()
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(internal.DefaultLayout.Encode(11, 3, 1)))
+	})
+
+	t.Run("all nodes exhausted", func(t *testing.T) {
+		testInstance, _ := internal.NewMultiNodeGenerator(snowflaketest.NewManualClock(10), 1, []uint8{3, 5}, nil, internal.DefaultLayout)
+		_, _ = testInstance.Next()
+		_, _ = testInstance.Next()
+
+		c := make(chan struct{})
+		go func() {
+			// this is expected to block forever as the clock does not make any progress
+			_, _ = testInstance.Next()
+			c <- struct{}{}
+		}()
+
+		select {
+		case <-c:
+			t.Fatal("this should never ever called")
+		case <-time.After(10 * time.Millisecond):
+		}
+	})
+
+	t.Run("clock skew", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflaketest.NewManualClock(10)
+		testInstance, _ := internal.NewMultiNodeGenerator(clock, 1, []uint8{3, 5}, nil, internal.DefaultLayout)
+		_, _ = testInstance.Next()
+		_, _ = testInstance.Next()
+
+		clock.Set(9)
+		_, err := testInstance.Next()
+		assert.That(err, is.EqualTo(internal.ErrClockNotMonotonic))
+	})
+
+	t.Run("unused node after clock skew", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflaketest.NewManualClock(10)
+		testInstance, _ := internal.NewMultiNodeGenerator(clock, 2, []uint8{3, 5}, nil, internal.DefaultLayout)
+		_, _ = testInstance.Next()
+
+		clock.Set(9)
+		r, err := testInstance.Next()
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(internal.DefaultLayout.Encode(9, 5, 1)))
+	})
+
+	t.Run("spare node on clock skew", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := snowflaketest.NewManualClock(10)
+		testInstance, err := internal.NewMultiNodeGenerator(clock, 2, []uint8{3}, []uint8{7, 8}, internal.DefaultLayout)
+		assert.That(err, is.Nil())
+
+		r, _ := testInstance.Next()
+		assert.That(r, is.EqualTo(internal.DefaultLayout.Encode(10, 3, 1)))
+
+		clock.Set(8)
+		r, _ = testInstance.Next()
+		assert.That(r, is.EqualTo(internal.DefaultLayout.Encode(8, 7, 1)))
+
+		clock.Set(9)
+		r, _ = testInstance.Next()
+		assert.That(r, is.EqualTo(internal.DefaultLayout.Encode(9, 7, 1)))
+
+		clock.Set(7)
+		r, _ = testInstance.Next()
+		assert.That(r, is.EqualTo(internal.DefaultLayout.Encode(7, 8, 1)))
+
+		clock.Set(10)
+		r, _ = testInstance.Next()
+		assert.That(r, is.EqualTo(internal.DefaultLayout.Encode(10, 3, 2)))
+
+		clock.Set(6)
+		_, err = testInstance.Next()
+		assert.That(err, is.EqualTo(internal.ErrClockNotMonotonic))
+	})
+
+	t.Run("spare overlaps node", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := internal.NewMultiNodeGenerator(snowflaketest.NewManualClock(10), 2, []uint8{3}, []uint8{3}, internal.DefaultLayout)
+		assert.That(err, is.EqualTo(internal.ErrNodeIDsInvalid))
+	})
+}
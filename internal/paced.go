@@ -0,0 +1,215 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	reservationPending = iota
+	reservationWaiting
+	reservationTaken
+	reservationCancelled
+)
+
+// Reservation is a slot reserved from a paced sequence provider, the sequence may be taken once its delay passed
+type Reservation struct {
+	provider *pacedSequenceProviderImpl
+	at       time.Time
+	state    int
+}
+
+// Delay returns how long to wait until the sequence can be taken, 0 if it can be taken right away
+func (r *Reservation) Delay() time.Duration {
+	if d := r.at.Sub(r.provider.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Cancel gives the slot back so later reservations are served earlier, it has no effect once the sequence was taken
+func (r *Reservation) Cancel() {
+	p := r.provider
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	r.cancel()
+}
+
+// cancel gives the slot back unless the reservation was taken or cancelled before, p.lock has to be held
+func (r *Reservation) cancel() {
+	if r.state == reservationTaken || r.state == reservationCancelled {
+		return
+	}
+	p := r.provider
+	r.state = reservationCancelled
+	p.tokens += 1
+	if p.tokens > p.burst {
+		p.tokens = p.burst
+	}
+}
+
+// Sequence waits out the delay and takes the sequence
+func (r *Reservation) Sequence() Sequence {
+	return r.Wait(context.Background())
+}
+
+// Wait is Sequence but gives up and cancels the reservation once ctx is done. It fails with ErrReservationUsed if
+// the reservation is already waited for, was taken or gets cancelled while waiting
+func (r *Reservation) Wait(ctx context.Context) Sequence {
+	p := r.provider
+	p.lock.Lock()
+	if r.state != reservationPending {
+		p.lock.Unlock()
+		return sequenceError(ErrReservationUsed)
+	}
+	r.state = reservationWaiting
+	p.lock.Unlock()
+
+	err := p.sleep(ctx, r.Delay())
+
+	p.lock.Lock()
+	if err != nil {
+		r.cancel()
+		p.lock.Unlock()
+		return sequenceError(err)
+	}
+	if r.state != reservationWaiting {
+		p.lock.Unlock()
+		return sequenceError(ErrReservationUsed)
+	}
+	r.state = reservationTaken
+	p.lock.Unlock()
+	return p.seq.Sequence()
+}
+
+// pacedSequenceProviderImpl is a token bucket in front of a sequence provider, it spreads the sequences evenly over
+// the tick instead of handing out the whole budget at once and then waiting for the next tick. On top of the bucket
+// at most maxSequence reservations are scheduled into a tick of the clock, so a burst never exhausts the sequence
+type pacedSequenceProviderImpl struct {
+	seq         SequenceProvider
+	clock       Clock
+	tick        time.Duration
+	interval    time.Duration
+	maxSequence uint16
+	burst       float64
+	now         func() time.Time
+	sleep       func(context.Context, time.Duration) error
+
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+
+	// current is the tick of the clock seen last, started the time it was seen first
+	current uint64
+	started time.Time
+	// scheduled is the tick the latest reservation falls into, count the reservations scheduled into it
+	scheduled uint64
+	count     uint16
+}
+
+// NewPacedSequenceProvider returns a provider handing out the sequences of seq at a rate of maxSequence per tick of
+// clock, one every tick/maxSequence. Up to burst sequences can be taken at once after an idle period. The time
+// within a tick is read from clock if it has a Now() time.Time method, otherwise from time.Now
+func NewPacedSequenceProvider(seq SequenceProvider, clock Clock, tick time.Duration, maxSequence uint16, burst int,
+	sleep func(context.Context, time.Duration) error) *pacedSequenceProviderImpl {
+	now := time.Now
+	if n, ok := clock.(nowClock); ok {
+		now = n.Now
+	}
+	current := now()
+	return &pacedSequenceProviderImpl{
+		seq:         seq,
+		clock:       clock,
+		tick:        tick,
+		interval:    tick / time.Duration(maxSequence),
+		maxSequence: maxSequence,
+		burst:       float64(burst),
+		now:         now,
+		sleep:       sleep,
+		tokens:      float64(burst),
+		last:        current,
+		current:     clock.Seconds(),
+		started:     current,
+		scheduled:   clock.Seconds(),
+	}
+}
+
+func (p *pacedSequenceProviderImpl) Sequence() Sequence {
+	return p.Reserve().Sequence()
+}
+
+// Wait takes the next sequence, it gives up once ctx is done
+func (p *pacedSequenceProviderImpl) Wait(ctx context.Context) Sequence {
+	return p.Reserve().Wait(ctx)
+}
+
+// Reserve reserves the next slot, the caller has to take its sequence or cancel it
+func (p *pacedSequenceProviderImpl) Reserve() *Reservation {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := p.now()
+	if current := p.clock.Seconds(); current != p.current {
+		if current < p.current {
+			p.scheduled, p.count = current, 0
+		}
+		p.current, p.started = current, now
+	}
+
+	if elapsed := now.Sub(p.last); elapsed > 0 {
+		p.tokens += float64(elapsed) / float64(p.interval)
+		if p.tokens > p.burst {
+			p.tokens = p.burst
+		}
+		p.last = now
+	}
+
+	p.tokens -= 1
+	at := now
+	if p.tokens < 0 {
+		at = now.Add(time.Duration(-p.tokens * float64(p.interval)))
+	}
+	if scheduled := p.schedule(at); scheduled.After(at) {
+		at = scheduled
+		p.tokens = -float64(at.Sub(now)) / float64(p.interval)
+	}
+	return &Reservation{provider: p, at: at}
+}
+
+// schedule returns the time the reservation due at can be taken without exceeding maxSequence per tick of the clock.
+// The start of a tick is estimated from when the clock was seen to change, which is never before its actual start
+func (p *pacedSequenceProviderImpl) schedule(at time.Time) time.Time {
+	tick := p.current
+	if elapsed := at.Sub(p.started); elapsed > 0 {
+		tick += uint64(elapsed / p.tick)
+	}
+	if tick > p.scheduled {
+		p.scheduled, p.count = tick, 0
+	}
+	if p.count >= p.maxSequence {
+		p.scheduled, p.count = p.scheduled+1, 0
+	}
+	p.count++
+
+	if start := p.started.Add(time.Duration(p.scheduled-p.current) * p.tick); start.After(at) {
+		return start
+	}
+	return at
+}
+
+// SleepContext sleeps for d, it returns the error of ctx if ctx is done earlier
+func SleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"context"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSleeper advances the fake time instead of sleeping
+type fakeSleeper struct {
+	time  *fakeTime
+	slept []time.Duration
+}
+
+func (f *fakeSleeper) sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.slept = append(f.slept, d)
+	f.time.Advance(d)
+	return nil
+}
+
+// blockingSleeper blocks every sleep until release is closed
+type blockingSleeper struct {
+	entered chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (b *blockingSleeper) sleep(ctx context.Context, d time.Duration) error {
+	b.once.Do(func() { close(b.entered) })
+	<-b.release
+	return ctx.Err()
+}
+
+func newPacedTestInstance(burst int) (*pacedSequenceProviderImpl, *fakeSleeper) {
+	clock := &fakeTime{now: time.Unix(1000, 0)}
+	sleeper := &fakeSleeper{time: clock}
+	seq := NewTickSequenceProvider(clock, 4, time.Millisecond)
+	return NewPacedSequenceProvider(seq, clock, time.Second, 4, burst, sleeper.sleep), sleeper
+}
+
+func TestPacedSequenceProvider(t *testing.T) {
+	t.Run("spreads sequences over the tick", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance, sleeper := newPacedTestInstance(1)
+		for i := uint16(1); i <= 8; i++ {
+			r := testInstance.Sequence()
+			assert.That(r.Error, is.Nil())
+			assert.That(r.Seconds, is.EqualTo(uint64(1000)+uint64(i-1)/4))
+			assert.That(r.Iteration, is.EqualTo((i-1)%4+1))
+		}
+		assert.That(len(sleeper.slept), is.EqualTo(8))
+		assert.That(sleeper.slept[0], is.EqualTo(time.Duration(0)))
+		for _, d := range sleeper.slept[1:] {
+			assert.That(d, is.EqualTo(250*time.Millisecond))
+		}
+	})
+
+	t.Run("burst after idle", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance, sleeper := newPacedTestInstance(3)
+		sleeper.time.Advance(10 * time.Second)
+
+		for i := 0; i < 3; i++ {
+			assert.That(testInstance.Reserve().Delay(), is.EqualTo(time.Duration(0)))
+		}
+		assert.That(testInstance.Reserve().Delay(), is.EqualTo(250*time.Millisecond))
+	})
+
+	t.Run("cancel gives the slot back", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance, _ := newPacedTestInstance(1)
+		first := testInstance.Reserve()
+		assert.That(first.Delay(), is.EqualTo(time.Duration(0)))
+
+		second := testInstance.Reserve()
+		assert.That(second.Delay(), is.EqualTo(250*time.Millisecond))
+		second.Cancel()
+
+		assert.That(testInstance.Reserve().Delay(), is.EqualTo(250*time.Millisecond))
+		assert.That(second.Sequence().Error, is.EqualTo(ErrReservationUsed))
+	})
+
+	t.Run("reservation used once", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance, _ := newPacedTestInstance(1)
+		r := testInstance.Reserve()
+		assert.That(r.Sequence().Error, is.Nil())
+		assert.That(r.Sequence().Error, is.EqualTo(ErrReservationUsed))
+	})
+
+	t.Run("burst does not exceed the sequence of a tick", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance, sleeper := newPacedTestInstance(3)
+		sleeper.time.Advance(10 * time.Second)
+
+		perTick := map[uint64]int{}
+		for i := 0; i < 12; i++ {
+			r := testInstance.Reserve()
+			perTick[uint64(r.at.Unix())]++
+		}
+		assert.That(perTick[1010], is.EqualTo(4))
+		assert.That(perTick[1011], is.EqualTo(4))
+		assert.That(perTick[1012], is.EqualTo(4))
+	})
+
+	t.Run("paced by the clock", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance, sleeper := newPacedTestInstance(4)
+		sleeper.time.Advance(1500 * time.Millisecond)
+
+		for i := 0; i < 4; i++ {
+			assert.That(testInstance.Reserve().Delay(), is.EqualTo(time.Duration(0)))
+		}
+		assert.That(testInstance.Reserve().Delay(), is.EqualTo(time.Second))
+	})
+
+	t.Run("cancel while waiting", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		clock := &fakeTime{now: time.Unix(1000, 0)}
+		sleeper := &blockingSleeper{entered: make(chan struct{}), release: make(chan struct{})}
+		testInstance := NewPacedSequenceProvider(NewTickSequenceProvider(clock, 4, time.Millisecond), clock, time.Second, 4, 1, sleeper.sleep)
+		testInstance.Reserve()
+
+		r := testInstance.Reserve()
+		waited := make(chan Sequence)
+		go func() { waited <- r.Sequence() }()
+		<-sleeper.entered
+
+		assert.That(r.Sequence().Error, is.EqualTo(ErrReservationUsed))
+		r.Cancel()
+		close(sleeper.release)
+		assert.That((<-waited).Error, is.EqualTo(ErrReservationUsed))
+
+		testInstance.lock.Lock()
+		defer testInstance.lock.Unlock()
+		assert.That(testInstance.tokens, is.EqualTo(float64(0)))
+	})
+
+	t.Run("wait gives up when context is done", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		testInstance, _ := newPacedTestInstance(1)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.That(testInstance.Wait(ctx).Error, is.EqualTo(context.Canceled))
+		assert.That(testInstance.Reserve().Delay(), is.EqualTo(time.Duration(0)))
+	})
+}
+
+func TestSleepContext(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	assert.That(SleepContext(context.Background(), time.Millisecond), is.Nil())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	assert.That(SleepContext(ctx, time.Minute), is.EqualTo(context.DeadlineExceeded))
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+)
+
+// NewRandomStart returns a function picking the first iteration of a tick out of n, it is seeded once from
+// crypto/rand and safe for concurrent use
+func NewRandomStart() (func(n uint16) uint16, error) {
+	var seed [8]byte
+	if _, err := crand.Read(seed[:]); err != nil {
+		return nil, err
+	}
+
+	var lock sync.Mutex
+	rng := rand.New(rand.NewSource(int64(binary.LittleEndian.Uint64(seed[:]))))
+	return func(n uint16) uint16 {
+		if n == 0 {
+			return 0
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		return uint16(rng.Intn(int(n)))
+	}, nil
+}
@@ -34,42 +34,83 @@ type SequenceProvider interface {
 type sequenceProviderImpl struct {
 	clock        Clock
 	maxIteration uint16
+	wait         time.Duration
 	lock         sync.Mutex
 
 	currentSeconds   uint64
 	currentIteration uint16
+
+	// start picks the offset of the first iteration of a tick, nil starts every tick at 1
+	start  func(n uint16) uint16
+	offset uint16
 }
 
 func (s *sequenceProviderImpl) Sequence() Sequence {
+	for {
+		seq := s.TrySequence()
+		if seq.Error != ErrSequenceExhausted {
+			return seq
+		}
+		time.Sleep(s.waitDuration())
+	}
+}
+
+// TrySequence is Sequence but returns ErrSequenceExhausted instead of waiting for the next tick
+func (s *sequenceProviderImpl) TrySequence() Sequence {
 	s.lock.Lock()
+	defer s.lock.Unlock()
 
 	secondsSinceEpoch := s.clock.Seconds()
 
 	if secondsSinceEpoch < s.currentSeconds {
-		s.lock.Unlock()
 		return sequenceError(ErrClockNotMonotonic)
 	}
 
 	if secondsSinceEpoch != s.currentSeconds {
 		s.currentSeconds = secondsSinceEpoch
 		s.currentIteration = 0
+		if s.start != nil {
+			s.offset = s.start(s.maxIteration)
+		}
 	}
 
 	if s.currentIteration >= s.maxIteration {
-		time.Sleep(250 * time.Millisecond)
-		s.lock.Unlock()
-		return s.Sequence()
+		return sequenceError(ErrSequenceExhausted)
 	}
 
 	s.currentIteration += 1
+	return sequenceOk(s.currentSeconds, s.iteration())
+}
+
+// iteration returns the current iteration shifted by the offset of the tick, wrapping within 1 and maxIteration
+func (s *sequenceProviderImpl) iteration() uint16 {
+	if s.start == nil {
+		return s.currentIteration
+	}
+	return uint16((uint32(s.offset)+uint32(s.currentIteration)-1)%uint32(s.maxIteration) + 1)
+}
+
+// SetStart makes every tick start at the iteration after the offset picked by start instead of at 1, the iterations
+// wrap around so a tick still has maxIteration unique iterations
+func (s *sequenceProviderImpl) SetStart(start func(n uint16) uint16) {
+	s.lock.Lock()
 	defer s.lock.Unlock()
-	return sequenceOk(s.currentSeconds, s.currentIteration)
+	s.start = start
+	s.offset = start(s.maxIteration)
+}
+
+// waitDuration returns how long to wait for the next tick once the sequence is exhausted
+func (s *sequenceProviderImpl) waitDuration() time.Duration {
+	if s.wait == 0 {
+		return defaultWait
+	}
+	return s.wait
 }
 
 //NewSequenceProvider returns and starts a new sequence provider, can be stopped by invoking Close()
-func NewSequenceProvider(clock Clock, maxSequence uint16) (*sequenceProviderImpl, error) {
+func NewSequenceProvider(clock Clock, maxSequence uint16, layout Layout) (*sequenceProviderImpl, error) {
 
-	if maxSequence > MaxSequence {
+	if maxSequence > layout.MaxSequence() {
 		return nil, ErrMaxSequenceOutOfRange
 	}
 
@@ -81,3 +122,14 @@ func NewSequenceProvider(clock Clock, maxSequence uint16) (*sequenceProviderImpl
 
 	return r, nil
 }
+
+// NewTickSequenceProvider returns a sequence provider for clocks ticking faster than once per second,
+// wait is the time it waits for the next tick once the sequence is exhausted
+func NewTickSequenceProvider(clock Clock, maxSequence uint16, wait time.Duration) *sequenceProviderImpl {
+	return &sequenceProviderImpl{
+		clock:        clock,
+		lock:         sync.Mutex{},
+		maxIteration: maxSequence,
+		wait:         wait,
+	}
+}
@@ -79,7 +79,7 @@ func (i *incrementalClock) Seconds() uint64 {
 
 func TestSequenceProviderImpl(t *testing.T) {
 	assert := hamcrest.NewAssertion(t)
-	testInstance, err := NewSequenceProvider(&incrementalClock{}, 100)
+	testInstance, err := NewSequenceProvider(&incrementalClock{}, 100, DefaultLayout)
 	assert.That(err, is.Nil())
 
 	for j := 0; j < 2; j++ {
@@ -94,7 +94,7 @@ func TestSequenceProviderImpl(t *testing.T) {
 
 func TestSequenceProvider_SequenceExhaustion(t *testing.T) {
 	assert := hamcrest.NewAssertion(t)
-	testInstance, err := NewSequenceProvider(&incrementalClock{}, 5)
+	testInstance, err := NewSequenceProvider(&incrementalClock{}, 5, DefaultLayout)
 	assert.That(err, is.Nil())
 
 	for i := 0; i < 5; i++ {
@@ -111,3 +111,70 @@ func TestSequenceProvider_SequenceExhaustion(t *testing.T) {
 		assert.That(seq.Error, is.Nil())
 	}
 }
+
+func TestNewSequenceProvider_MaxSequenceOutOfRange(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	_, err := NewSequenceProvider(fakeClock{}, 1024, Layout{46, 8, 10})
+	assert.That(err, is.EqualTo(ErrMaxSequenceOutOfRange))
+}
+
+func TestTrySequence(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	testInstance := NewTickSequenceProvider(fakeClock{10}, 1, 0)
+
+	seq := testInstance.TrySequence()
+	assert.That(seq.Error, is.Nil())
+	assert.That(seq.Iteration, is.EqualTo(uint16(1)))
+
+	seq = testInstance.TrySequence()
+	assert.That(seq.Error, is.EqualTo(ErrSequenceExhausted))
+}
+
+func TestSequenceProvider_SetStart(t *testing.T) {
+	t.Run("wraps within max iteration", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		testInstance := NewTickSequenceProvider(fakeClock{10}, 5, 0)
+		testInstance.SetStart(func(n uint16) uint16 { return 3 })
+
+		for _, expected := range []uint16{4, 5, 1, 2, 3} {
+			seq := testInstance.TrySequence()
+			assert.That(seq.Error, is.Nil())
+			assert.That(seq.Iteration, is.EqualTo(expected))
+		}
+		assert.That(testInstance.TrySequence().Error, is.EqualTo(ErrSequenceExhausted))
+	})
+
+	t.Run("unique per tick", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		start, err := NewRandomStart()
+		assert.That(err, is.Nil())
+
+		testInstance := NewTickSequenceProvider(fakeClock{10}, MaxSequence, 0)
+		testInstance.SetStart(start)
+
+		seen := make(map[uint16]struct{}, MaxSequence)
+		for i := 0; i < int(MaxSequence); i++ {
+			seq := testInstance.TrySequence()
+			assert.That(seq.Error, is.Nil())
+			assert.That(seq.Iteration >= 1 && seq.Iteration <= MaxSequence, is.True())
+			seen[seq.Iteration] = struct{}{}
+		}
+		assert.That(len(seen), is.EqualTo(int(MaxSequence)))
+		assert.That(testInstance.TrySequence().Error, is.EqualTo(ErrSequenceExhausted))
+	})
+}
+
+func TestNewRandomStart(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	start, err := NewRandomStart()
+	assert.That(err, is.Nil())
+
+	assert.That(start(0), is.EqualTo(uint16(0)))
+	seen := map[uint16]struct{}{}
+	for i := 0; i < 1000; i++ {
+		r := start(16)
+		assert.That(r < 16, is.True())
+		seen[r] = struct{}{}
+	}
+	assert.That(len(seen) > 1, is.True())
+}
@@ -7,6 +7,7 @@ type SnowflakeGenerator interface {
 type snowFlakeGeneratorImpl struct {
 	seqProvider SequenceProvider
 	nodeID      uint8
+	layout      Layout
 }
 
 func (s *snowFlakeGeneratorImpl) Next() (uint64, error) {
@@ -15,16 +16,22 @@ func (s *snowFlakeGeneratorImpl) Next() (uint64, error) {
 		return 0, seq.Error
 	}
 
-	id := seq.Seconds << uint64(totalBits-epochBits)
-	id |= uint64(s.nodeID) << uint64(totalBits-epochBits-nodeBits)
-	id |= uint64(seq.Iteration)
-
-	return id, nil
+	return s.layout.Pack(seq.Seconds, s.nodeID, seq.Iteration)
 }
 
-func NewGenerator(seq SequenceProvider, node NodeIDProvider) (SnowflakeGenerator, error) {
+func NewGenerator(seq SequenceProvider, node NodeIDProvider, layout Layout) (SnowflakeGenerator, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+
+	nodeID := node.ID()
+	if nodeID > layout.MaxNodeID() {
+		return nil, ErrNodeIDOutOfRange
+	}
+
 	return &snowFlakeGeneratorImpl{
 		seqProvider: seq,
-		nodeID:      node.ID(),
+		nodeID:      nodeID,
+		layout:      layout,
 	}, nil
 }
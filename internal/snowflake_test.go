@@ -20,12 +20,13 @@ func TestSnowFlakeGeneratorImpl_Next(t *testing.T) {
 	t.Run("within same ms", func(t *testing.T) {
 		assert := hamcrest.NewAssertion(t)
 
-		seqProvider, err := NewSequenceProvider(fakeClock{10}, 10)
+		seqProvider, err := NewSequenceProvider(fakeClock{10}, 10, DefaultLayout)
 		assert.That(err, is.Nil())
 
 		testInstance, err := NewGenerator(
 			seqProvider,
 			fixedNodeIdProviderImpl{42},
+			DefaultLayout,
 		)
 		assert.That(err, is.Nil())
 
@@ -60,12 +61,13 @@ func TestSnowFlakeGeneratorImpl_Next(t *testing.T) {
 	t.Run("once per s", func(t *testing.T) {
 		assert := hamcrest.NewAssertion(t)
 
-		seqProvider, err := NewSequenceProvider(&countUpClock{}, 10)
+		seqProvider, err := NewSequenceProvider(&countUpClock{}, 10, DefaultLayout)
 		assert.That(err, is.Nil())
 
 		testInstance, err := NewGenerator(
 			seqProvider,
 			fixedNodeIdProviderImpl{42},
+			DefaultLayout,
 		)
 		assert.That(err, is.Nil())
 
@@ -102,12 +104,13 @@ func TestSnowFlakeGeneratorImpl_Next(t *testing.T) {
 		t.Run("1", func(t *testing.T) {
 			assert := hamcrest.NewAssertion(t)
 
-			seqProvider, err := NewSequenceProvider(fakeClock{10}, 10)
+			seqProvider, err := NewSequenceProvider(fakeClock{10}, 10, DefaultLayout)
 			assert.That(err, is.Nil())
 
 			testInstance, err := NewGenerator(
 				seqProvider,
 				fixedNodeIdProviderImpl{1},
+				DefaultLayout,
 			)
 			assert.That(err, is.Nil())
 
@@ -120,12 +123,13 @@ func TestSnowFlakeGeneratorImpl_Next(t *testing.T) {
 		t.Run("2", func(t *testing.T) {
 			assert := hamcrest.NewAssertion(t)
 
-			seqProvider, err := NewSequenceProvider(fakeClock{10}, 10)
+			seqProvider, err := NewSequenceProvider(fakeClock{10}, 10, DefaultLayout)
 			assert.That(err, is.Nil())
 
 			testInstance, err := NewGenerator(
 				seqProvider,
 				fixedNodeIdProviderImpl{2},
+				DefaultLayout,
 			)
 			assert.That(err, is.Nil())
 
@@ -136,3 +140,29 @@ func TestSnowFlakeGeneratorImpl_Next(t *testing.T) {
 		})
 	})
 }
+
+func TestNewGenerator_Invalid(t *testing.T) {
+	t.Run("invalid layout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := NewGenerator(nil, fixedNodeIdProviderImpl{1}, Layout{42, 8, 8})
+		assert.That(err, is.EqualTo(ErrLayoutInvalid))
+	})
+	t.Run("node id exceeds layout", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := NewGenerator(nil, fixedNodeIdProviderImpl{16}, Layout{46, 4, 14})
+		assert.That(err, is.EqualTo(ErrNodeIDOutOfRange))
+	})
+}
+
+func TestSnowFlakeGeneratorImpl_Next_TimestampOverflow(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	seqProvider, err := NewSequenceProvider(fakeClock{1 << 42}, 10, DefaultLayout)
+	assert.That(err, is.Nil())
+
+	testInstance, err := NewGenerator(seqProvider, fixedNodeIdProviderImpl{1}, DefaultLayout)
+	assert.That(err, is.Nil())
+
+	_, err = testInstance.Next()
+	assert.That(err, is.EqualTo(ErrTimestampOverflow))
+}
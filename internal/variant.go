@@ -0,0 +1,111 @@
+package internal
+
+// Variant describes the bits of an ID of a foreign snowflake format. Unlike Layout the node may use up to 16 bits,
+// the top bits may be left unused and the sequence may be stored above the node:
+// |--Unused--|--Timestamp--|--Node--|--Sequence--| or |--Unused--|--Timestamp--|--Sequence--|--Node--|
+type Variant struct {
+	TimestampBits uint8
+	NodeBits      uint8
+	SequenceBits  uint8
+	SequenceFirst bool
+}
+
+// Validate checks that the variant uses at most 64 bits and that node and sequence fit into their go types
+func (v Variant) Validate() error {
+	if v.TimestampBits == 0 || v.SequenceBits == 0 {
+		return ErrVariantInvalid
+	}
+	if v.NodeBits > 16 || v.SequenceBits > 16 {
+		return ErrVariantInvalid
+	}
+	if int(v.TimestampBits)+int(v.NodeBits)+int(v.SequenceBits) > totalBits {
+		return ErrVariantInvalid
+	}
+	return nil
+}
+
+// MaxTimestamp returns the largest timestamp which can be stored
+func (v Variant) MaxTimestamp() uint64 {
+	return mask(v.TimestampBits)
+}
+
+// MaxNodeID returns the largest node id which can be stored
+func (v Variant) MaxNodeID() uint16 {
+	return uint16(mask(v.NodeBits))
+}
+
+// MaxSequence returns the largest sequence which can be stored
+func (v Variant) MaxSequence() uint16 {
+	return uint16(mask(v.SequenceBits))
+}
+
+// Encode packs the components into an ID, components exceeding their width get truncated
+func (v Variant) Encode(timestamp uint64, nodeID uint16, sequence uint16) uint64 {
+	node := uint64(nodeID) & mask(v.NodeBits)
+	seq := uint64(sequence) & mask(v.SequenceBits)
+
+	id := (timestamp & v.MaxTimestamp()) << (v.NodeBits + v.SequenceBits)
+	if v.SequenceFirst {
+		return id | seq<<v.NodeBits | node
+	}
+	return id | node<<v.SequenceBits | seq
+}
+
+// Pack is Encode but rejects components exceeding their width instead of truncating them
+func (v Variant) Pack(timestamp uint64, nodeID uint16, sequence uint16) (uint64, error) {
+	if timestamp > v.MaxTimestamp() {
+		return 0, ErrTimestampOverflow
+	}
+	if nodeID > v.MaxNodeID() {
+		return 0, ErrNodeIDOutOfRange
+	}
+	if sequence > v.MaxSequence() {
+		return 0, ErrSequenceOutOfRange
+	}
+	return v.Encode(timestamp, nodeID, sequence), nil
+}
+
+// Decode unpacks an ID into its components, unused top bits are ignored
+func (v Variant) Decode(id uint64) (timestamp uint64, nodeID uint16, sequence uint16) {
+	timestamp = (id >> (v.NodeBits + v.SequenceBits)) & v.MaxTimestamp()
+	if v.SequenceFirst {
+		sequence = uint16((id >> v.NodeBits) & mask(v.SequenceBits))
+		nodeID = uint16(id & mask(v.NodeBits))
+		return
+	}
+	nodeID = uint16((id >> v.SequenceBits) & mask(v.NodeBits))
+	sequence = uint16(id & mask(v.SequenceBits))
+	return
+}
+
+type variantGeneratorImpl struct {
+	seqProvider SequenceProvider
+	nodeID      uint16
+	variant     Variant
+}
+
+func (g *variantGeneratorImpl) Next() (uint64, error) {
+	seq := g.seqProvider.Sequence()
+	if seq.Error != nil {
+		return 0, seq.Error
+	}
+
+	return g.variant.Pack(seq.Seconds, g.nodeID, seq.Iteration)
+}
+
+// NewVariantGenerator returns a generator for IDs of variant, the sequence provider delivers ticks instead of seconds
+func NewVariantGenerator(seq SequenceProvider, nodeID uint16, variant Variant) (SnowflakeGenerator, error) {
+	if err := variant.Validate(); err != nil {
+		return nil, err
+	}
+
+	if nodeID > variant.MaxNodeID() {
+		return nil, ErrNodeIDOutOfRange
+	}
+
+	return &variantGeneratorImpl{
+		seqProvider: seq,
+		nodeID:      nodeID,
+		variant:     variant,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"testing"
+)
+
+func TestVariant_Validate(t *testing.T) {
+	t.Run("less than 64 bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Variant{TimestampBits: 39, NodeBits: 16, SequenceBits: 8}.Validate(), is.Nil())
+	})
+	t.Run("more than 64 bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Variant{TimestampBits: 42, NodeBits: 16, SequenceBits: 8}.Validate(), is.EqualTo(ErrVariantInvalid))
+	})
+	t.Run("node bits exceed uint16", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Variant{TimestampBits: 30, NodeBits: 17, SequenceBits: 8}.Validate(), is.EqualTo(ErrVariantInvalid))
+	})
+	t.Run("no sequence bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Variant{TimestampBits: 41, NodeBits: 10}.Validate(), is.EqualTo(ErrVariantInvalid))
+	})
+	t.Run("no timestamp bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		assert.That(Variant{NodeBits: 10, SequenceBits: 12}.Validate(), is.EqualTo(ErrVariantInvalid))
+	})
+}
+
+func TestVariant_Max(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	testInstance := Variant{TimestampBits: 41, NodeBits: 13, SequenceBits: 10}
+	assert.That(testInstance.MaxTimestamp(), is.EqualTo(uint64(2199023255551)))
+	assert.That(testInstance.MaxNodeID(), is.EqualTo(uint16(8191)))
+	assert.That(testInstance.MaxSequence(), is.EqualTo(uint16(1023)))
+}
+
+func TestVariant_Encode(t *testing.T) {
+	t.Run("node first", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r := Variant{TimestampBits: 41, NodeBits: 10, SequenceBits: 12}.Encode(5, 513, 3)
+		assert.That(fmt.Sprintf("%064b", r), is.EqualTo("0000000000000000000000000000000000000001011000000001000000000011"))
+	})
+	t.Run("sequence first", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r := Variant{TimestampBits: 39, NodeBits: 16, SequenceBits: 8, SequenceFirst: true}.Encode(5, 513, 3)
+		assert.That(fmt.Sprintf("%064b", r), is.EqualTo("0000000000000000000000000000000000000101000000110000001000000001"))
+	})
+	t.Run("truncates", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r := Variant{TimestampBits: 39, NodeBits: 16, SequenceBits: 8, SequenceFirst: true}.Encode(1<<39, 0, 256)
+		assert.That(r, is.EqualTo(uint64(0)))
+	})
+}
+
+func TestVariant_Pack(t *testing.T) {
+	testInstance := Variant{TimestampBits: 41, NodeBits: 13, SequenceBits: 10}
+
+	t.Run("ok", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := testInstance.Pack(1, 8191, 1023)
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(testInstance.Encode(1, 8191, 1023)))
+	})
+	t.Run("timestamp overflow", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := testInstance.Pack(1<<41, 0, 0)
+		assert.That(err, is.EqualTo(ErrTimestampOverflow))
+	})
+	t.Run("node out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := testInstance.Pack(0, 8192, 0)
+		assert.That(err, is.EqualTo(ErrNodeIDOutOfRange))
+	})
+	t.Run("sequence out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := testInstance.Pack(0, 0, 1024)
+		assert.That(err, is.EqualTo(ErrSequenceOutOfRange))
+	})
+}
+
+func TestVariant_Decode(t *testing.T) {
+	for _, variant := range []Variant{
+		{TimestampBits: 41, NodeBits: 10, SequenceBits: 12},
+		{TimestampBits: 42, NodeBits: 10, SequenceBits: 12},
+		{TimestampBits: 39, NodeBits: 16, SequenceBits: 8, SequenceFirst: true},
+		{TimestampBits: 41, NodeBits: 13, SequenceBits: 10},
+	} {
+		t.Run(fmt.Sprintf("%+v", variant), func(t *testing.T) {
+			assert := hamcrest.NewAssertion(t)
+			timestamp, nodeID, sequence := variant.Decode(variant.Encode(123456789, variant.MaxNodeID(), 7))
+			assert.That(timestamp, is.EqualTo(uint64(123456789)))
+			assert.That(nodeID, is.EqualTo(variant.MaxNodeID()))
+			assert.That(sequence, is.EqualTo(uint16(7)))
+		})
+	}
+
+	t.Run("ignores unused bits", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		timestamp, _, _ := Variant{TimestampBits: 41, NodeBits: 10, SequenceBits: 12}.Decode(1<<63 | 1<<22)
+		assert.That(timestamp, is.EqualTo(uint64(1)))
+	})
+}
+
+func TestNewVariantGenerator(t *testing.T) {
+	variant := Variant{TimestampBits: 41, NodeBits: 10, SequenceBits: 12}
+
+	t.Run("node out of range", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := NewVariantGenerator(NewTickSequenceProvider(fakeClock{1}, 10, 0), 1024, variant)
+		assert.That(err, is.EqualTo(ErrNodeIDOutOfRange))
+	})
+
+	t.Run("invalid variant", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := NewVariantGenerator(NewTickSequenceProvider(fakeClock{1}, 10, 0), 1, Variant{})
+		assert.That(err, is.EqualTo(ErrVariantInvalid))
+	})
+
+	t.Run("next", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		testInstance, err := NewVariantGenerator(NewTickSequenceProvider(fakeClock{5}, 10, 0), 513, variant)
+		assert.That(err, is.Nil())
+
+		r, err := testInstance.Next()
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(variant.Encode(5, 513, 1)))
+	})
+}
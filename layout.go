@@ -0,0 +1,88 @@
+package snowflake
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-snowflake/internal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout describes how the 64 bits of an ID are split between timestamp, node and sequence
+// Layout format:   |-----TimestampBits-----|-----NodeBits-----|-----SequenceBits-----|
+// The bits must add up to 64, NodeBits can be at most 8 and SequenceBits between 1 and 16
+type Layout struct {
+	TimestampBits uint8
+	NodeBits      uint8
+	SequenceBits  uint8
+}
+
+var (
+	// DefaultLayout uses 42 timestamp bits, 8 node bits and 14 sequence bits
+	DefaultLayout = Layout(internal.DefaultLayout)
+)
+
+// ParseLayout parses a layout in the form timestamp:node:sequence, e.g. 42:8:14
+func ParseLayout(s string) (Layout, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return Layout{}, fmt.Errorf("layout %q is not in the form timestamp:node:sequence", s)
+	}
+
+	var bits [3]uint8
+	for i, part := range parts {
+		v, err := strconv.ParseUint(part, 10, 8)
+		if err != nil {
+			return Layout{}, fmt.Errorf("layout %q is not in the form timestamp:node:sequence", s)
+		}
+		bits[i] = uint8(v)
+	}
+
+	r := Layout{TimestampBits: bits[0], NodeBits: bits[1], SequenceBits: bits[2]}
+	if err := r.Validate(); err != nil {
+		return Layout{}, err
+	}
+	return r, nil
+}
+
+// Validate checks that the layout uses exactly 64 bits and that node and sequence fit into their go types
+func (l Layout) Validate() error {
+	return l.internal().Validate()
+}
+
+// MaxNodeID returns the largest node id the layout can store
+func (l Layout) MaxNodeID() uint8 {
+	return l.internal().MaxNodeID()
+}
+
+// MaxSequence returns the largest sequence the layout can store
+func (l Layout) MaxSequence() uint16 {
+	return l.internal().MaxSequence()
+}
+
+// Horizon returns the date at which IDs counting from epoch (in seconds since the UNIX epoch) run out of timestamp bits
+func (l Layout) Horizon(epoch uint64) time.Time {
+	return l.internal().Horizon(epoch)
+}
+
+// Encode returns the ID consisting of the given components, components exceeding the layout are rejected
+func (l Layout) Encode(seconds uint64, nodeID uint8, sequence uint16) (ID, error) {
+	id, err := l.internal().Pack(seconds, nodeID, sequence)
+	if err != nil {
+		return nil, err
+	}
+	return l.From(id), nil
+}
+
+// From decodes an ID which was generated with this layout
+func (l Layout) From(id uint64) ID {
+	return &idImpl{id, l.internal(), 0, nil}
+}
+
+func (l Layout) String() string {
+	return fmt.Sprintf("%d:%d:%d", l.TimestampBits, l.NodeBits, l.SequenceBits)
+}
+
+func (l Layout) internal() internal.Layout {
+	return internal.Layout(l)
+}
@@ -0,0 +1,268 @@
+package snowflake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMonitorTolerance is the drift between wall and monotonic time the ClockMonitor accepts
+	DefaultMonitorTolerance = 50 * time.Millisecond
+	// DefaultMonitorRecovery is the number of clean checks after which the ClockMonitor trusts the clock again
+	DefaultMonitorRecovery = 3
+)
+
+// ClockReading is the wall time and the monotonic time at the same instant
+type ClockReading struct {
+	Wall      time.Time
+	Monotonic time.Duration
+}
+
+// ClockEventKind classifies how the wall time diverged from the monotonic time
+type ClockEventKind int
+
+const (
+	// ClockJump is a wall time moving faster than the monotonic time, e.g. an NTP step forward
+	ClockJump ClockEventKind = iota + 1
+	// ClockRollback is a wall time moving slower than the monotonic time or going backwards
+	ClockRollback
+	// ClockRepeat is a wall time repeating a second, like a leap second
+	ClockRepeat
+	// ClockStall is a wall time standing still while the monotonic time moves
+	ClockStall
+)
+
+func (k ClockEventKind) String() string {
+	switch k {
+	case ClockJump:
+		return "jump"
+	case ClockRollback:
+		return "rollback"
+	case ClockRepeat:
+		return "repeat"
+	case ClockStall:
+		return "stall"
+	}
+	return fmt.Sprintf("ClockEventKind(%d)", int(k))
+}
+
+// ClockEvent is a divergence detected by the ClockMonitor, Drift is the wall progression minus the monotonic one
+type ClockEvent struct {
+	Kind  ClockEventKind
+	Wall  time.Time
+	Drift time.Duration
+}
+
+func (e ClockEvent) String() string {
+	return fmt.Sprintf("clock %s by %s at %s", e.Kind, e.Drift, e.Wall.Format(time.RFC3339Nano))
+}
+
+// ClockMonitor periodically compares the progression of the wall time against the monotonic time. After an event
+// it reports the clock as untrustworthy until the following checks are clean again. It has to be stopped by invoking Close()
+type ClockMonitor struct {
+	interval  time.Duration
+	tolerance time.Duration
+	recovery  int
+	read      func() ClockReading
+	callback  func(ClockEvent)
+
+	lock      sync.Mutex
+	last      ClockReading
+	moved     ClockReading
+	lastEvent *ClockEvent
+	clean     int
+
+	done chan struct{}
+	once sync.Once
+}
+
+type MonitorOption func(*ClockMonitor) error
+
+// WithMonitorTolerance sets the drift which is accepted between two checks. By default DefaultMonitorTolerance
+func WithMonitorTolerance(tolerance time.Duration) MonitorOption {
+	return func(m *ClockMonitor) error {
+		if tolerance <= 0 {
+			return ErrMonitorInvalid
+		}
+		m.tolerance = tolerance
+		return nil
+	}
+}
+
+// WithMonitorRecovery sets the number of clean checks after which the clock is trusted again. By default DefaultMonitorRecovery
+func WithMonitorRecovery(checks int) MonitorOption {
+	return func(m *ClockMonitor) error {
+		if checks <= 0 {
+			return ErrMonitorInvalid
+		}
+		m.recovery = checks
+		return nil
+	}
+}
+
+// WithMonitorCallback sets a callback invoked for every detected event
+func WithMonitorCallback(callback func(ClockEvent)) MonitorOption {
+	return func(m *ClockMonitor) error {
+		m.callback = callback
+		return nil
+	}
+}
+
+// WithMonitorReadings replaces the source of the readings, mainly useful for tests. By default time.Now
+func WithMonitorReadings(read func() ClockReading) MonitorOption {
+	return func(m *ClockMonitor) error {
+		m.read = read
+		return nil
+	}
+}
+
+// systemReadings returns readings of time.Now, the wall time is stripped of its monotonic part
+func systemReadings() func() ClockReading {
+	start := time.Now()
+	return func() ClockReading {
+		now := time.Now()
+		return ClockReading{Wall: now.Round(0), Monotonic: now.Sub(start)}
+	}
+}
+
+// NewClockMonitor returns and starts a monitor checking the clock every interval
+func NewClockMonitor(interval time.Duration, options ...MonitorOption) (*ClockMonitor, error) {
+	if interval <= 0 {
+		return nil, ErrMonitorInvalid
+	}
+
+	r := &ClockMonitor{
+		interval:  interval,
+		tolerance: DefaultMonitorTolerance,
+		recovery:  DefaultMonitorRecovery,
+		read:      systemReadings(),
+		done:      make(chan struct{}),
+	}
+
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	r.last = r.read()
+	r.moved = r.last
+	go r.run()
+	return r, nil
+}
+
+func (m *ClockMonitor) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.Check()
+		}
+	}
+}
+
+// Check compares the current reading against the previous one, it returns the detected event if any
+func (m *ClockMonitor) Check() (ClockEvent, bool) {
+	m.lock.Lock()
+	current := m.read()
+	event, ok := m.classify(current)
+	if !current.Wall.Equal(m.last.Wall) {
+		m.moved = current
+	}
+	m.last = current
+	if ok {
+		m.lastEvent = &event
+		m.clean = 0
+	} else {
+		m.clean++
+	}
+	callback := m.callback
+	m.lock.Unlock()
+
+	if ok && callback != nil {
+		callback(event)
+	}
+	return event, ok
+}
+
+// classify compares current against the last reading. A wall time standing still since it last moved is a stall once
+// the monotonic time moved on by more than the tolerance, no matter how many checks that took. Only a wall time
+// standing for a single check of one second is a repeated second
+func (m *ClockMonitor) classify(current ClockReading) (ClockEvent, bool) {
+	if current.Wall.Equal(m.moved.Wall) {
+		standing := current.Monotonic - m.moved.Monotonic
+		event := ClockEvent{Wall: current.Wall, Drift: -standing}
+
+		switch {
+		case standing <= m.tolerance:
+			return ClockEvent{}, false
+		case m.last.Monotonic == m.moved.Monotonic && abs(standing-time.Second) <= m.tolerance:
+			event.Kind = ClockRepeat
+		default:
+			event.Kind = ClockStall
+		}
+		return event, true
+	}
+
+	wall := current.Wall.Sub(m.last.Wall)
+	drift := wall - (current.Monotonic - m.last.Monotonic)
+	event := ClockEvent{Wall: current.Wall, Drift: drift}
+
+	switch {
+	case abs(drift) <= m.tolerance:
+		return ClockEvent{}, false
+	case abs(drift+time.Second) <= m.tolerance:
+		event.Kind = ClockRepeat
+	case abs(wall) <= m.tolerance:
+		event.Kind = ClockStall
+	case drift < 0:
+		event.Kind = ClockRollback
+	default:
+		event.Kind = ClockJump
+	}
+	return event, true
+}
+
+// Healthy returns nil if the clock is trusted, otherwise ErrClockUntrustworthy describing the last event
+func (m *ClockMonitor) Healthy() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.lastEvent == nil || m.clean >= m.recovery {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrClockUntrustworthy, m.lastEvent)
+}
+
+// Close stops the periodic checks
+func (m *ClockMonitor) Close() error {
+	m.once.Do(func() { close(m.done) })
+	return nil
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// HealthReporter is implemented by generators created with WithClockMonitor
+type HealthReporter interface {
+	// Health returns nil if the generator can be trusted to issue IDs
+	Health() error
+}
+
+// WithClockMonitor reports the health of monitor as health of the generator, see HealthReporter.
+// Next keeps issuing IDs, it is up to the caller to refuse traffic while the generator is unhealthy
+func WithClockMonitor(monitor *ClockMonitor) Option {
+	return func(impl *generatorBuilderImpl) error {
+		impl.monitor = monitor
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"context"
+	"github.com/scarabsoft/go-snowflake/internal"
+	"time"
+)
+
+// Reservation is a slot reserved from a PacedSequenceProvider, see PacedSequenceProvider.Reserve
+type Reservation = internal.Reservation
+
+// PacedSequenceProvider hands out the sequences of a tick evenly spread over the tick like a token bucket,
+// so the maximal sequence acts as a smooth rate limit instead of a burst followed by a stall
+type PacedSequenceProvider interface {
+	SequenceProvider
+	// Wait is Sequence but gives up with the error of ctx once ctx is done
+	Wait(ctx context.Context) Sequence
+	// Reserve reserves the next slot without waiting, Delay tells how long until its sequence can be taken
+	// and Cancel gives it back. A reservation can be used once
+	Reserve() *Reservation
+}
+
+// NewPacedSequenceProvider returns a provider handing out maxSequence sequences per second of clock, one every
+// 1/maxSequence seconds. After an idle period up to burst sequences are handed out at once, but never more than
+// maxSequence within a second of clock
+func NewPacedSequenceProvider(clock Clock, maxSequence uint16, burst int) (PacedSequenceProvider, error) {
+	if maxSequence == 0 || burst < 1 {
+		return nil, ErrPacingInvalid
+	}
+	seq := internal.NewTickSequenceProvider(clock, maxSequence, 0)
+	return internal.NewPacedSequenceProvider(seq, clock, time.Second, maxSequence, burst, internal.SleepContext), nil
+}
+
+// WithPacing spreads the IDs of a second evenly over the second instead of issuing up to WithMaxSequence at once and
+// then waiting for the next second, up to burst IDs are issued at once after an idle period. Small bursts trade the
+// throughput of short peaks for an even latency. It can not be combined with WithNodeIDs or WithSpareNodeIDs
+func WithPacing(burst int) Option {
+	return func(impl *generatorBuilderImpl) error {
+		if burst < 1 {
+			return ErrPacingInvalid
+		}
+		impl.pacingBurst = burst
+		return nil
+	}
+}
@@ -0,0 +1,158 @@
+package snowflake
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// RegistryMode decides what NewGenerator does when the process wide node registry already holds the node id
+type RegistryMode int
+
+const (
+	// RegistryExclusive makes NewGenerator fail with ErrNodeIDInUse
+	RegistryExclusive RegistryMode = iota + 1
+	// RegistryShared makes NewGenerator return the live generator holding the node id
+	RegistryShared
+)
+
+// registryKey identifies IDs which would collide, the same node with the same epoch and layout
+type registryKey struct {
+	nodeID uint8
+	epoch  uint64
+	layout Layout
+}
+
+type registryEntry struct {
+	gen  *generatorImpl
+	keys []registryKey
+	refs int
+	// hybrid entries can only be shared by HLC generators
+	hybrid bool
+	// ready is closed once the generator was built, err holds the failure of the build
+	ready chan struct{}
+	err   error
+}
+
+var nodeRegistry = struct {
+	lock    sync.Mutex
+	entries map[registryKey]*registryEntry
+}{entries: make(map[registryKey]*registryEntry)}
+
+// WithNodeRegistry registers the node id in the process wide registry, so two live generators of one process can not
+// issue the same IDs. The returned generator implements io.Closer, Close releases the node id.
+// Generators created with WithNodeIDs or WithSpareNodeIDs register all their node ids
+func WithNodeRegistry(mode RegistryMode) Option {
+	return func(impl *generatorBuilderImpl) error {
+		impl.registryMode = mode
+		return nil
+	}
+}
+
+// newRegisteredGenerator creates the generator of r unless one of its node ids is already registered
+func newRegisteredGenerator(r *generatorBuilderImpl) (*registeredGeneratorImpl, error) {
+	if len(r.nodeIDs) == 0 {
+		r.nodeProvider = NewFixedNodeProvider(r.nodeProvider.ID())
+	}
+	nodeIDs := append(append([]uint8(nil), r.primaryNodeIDs()...), r.spareNodeIDs...)
+
+	keys := make([]registryKey, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		keys[i] = registryKey{nodeID: nodeID, epoch: r.codec().epochSeconds(), layout: r.layout}
+	}
+
+	nodeRegistry.lock.Lock()
+	if entry, ok := nodeRegistry.entries[keys[0]]; ok && r.registryMode == RegistryShared && entry.hybrid == r.hybrid && sameKeys(entry.keys, keys) {
+		entry.refs++
+		nodeRegistry.lock.Unlock()
+
+		<-entry.ready
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		return &registeredGeneratorImpl{generatorImpl: entry.gen, entry: entry}, nil
+	}
+	for _, key := range keys {
+		if _, ok := nodeRegistry.entries[key]; ok {
+			nodeRegistry.lock.Unlock()
+			return nil, ErrNodeIDInUse
+		}
+	}
+
+	// reserve the keys, the build may wait for the startup guard and must not block the registry
+	entry := &registryEntry{keys: keys, refs: 1, hybrid: r.hybrid, ready: make(chan struct{})}
+	for _, key := range keys {
+		nodeRegistry.entries[key] = entry
+	}
+	nodeRegistry.lock.Unlock()
+
+	gen, err := newGenerator(r)
+	if err != nil {
+		nodeRegistry.lock.Lock()
+		for _, key := range keys {
+			delete(nodeRegistry.entries, key)
+		}
+		nodeRegistry.lock.Unlock()
+
+		entry.err = err
+		close(entry.ready)
+		return nil, err
+	}
+
+	entry.gen = gen
+	close(entry.ready)
+	return &registeredGeneratorImpl{generatorImpl: gen, entry: entry}, nil
+}
+
+func sameKeys(a, b []registryKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func releaseNodeIDs(entry *registryEntry) {
+	nodeRegistry.lock.Lock()
+	defer nodeRegistry.lock.Unlock()
+
+	entry.refs--
+	if entry.refs == 0 {
+		for _, key := range entry.keys {
+			delete(nodeRegistry.entries, key)
+		}
+	}
+}
+
+// registeredGeneratorImpl is a handle on a registered generator, shared generators have one handle per NewGenerator call
+type registeredGeneratorImpl struct {
+	*generatorImpl
+	entry  *registryEntry
+	closed int32
+}
+
+func (g *registeredGeneratorImpl) Next() (ID, error) {
+	if atomic.LoadInt32(&g.closed) == 1 {
+		return nil, ErrGeneratorClosed
+	}
+	return g.generatorImpl.Next()
+}
+
+func (g *registeredGeneratorImpl) MustNext() ID {
+	if r, err := g.Next(); err != nil {
+		panic(err)
+	} else {
+		return r
+	}
+}
+
+// Close releases the node ids once all handles of the generator are closed
+func (g *registeredGeneratorImpl) Close() error {
+	if atomic.CompareAndSwapInt32(&g.closed, 0, 1) {
+		releaseNodeIDs(g.entry)
+	}
+	return nil
+}
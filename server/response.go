@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type idResponse struct {
+	ID string `json:"id"`
+}
+
+type idsResponse struct {
+	IDs []string `json:"ids"`
+}
+
+type decodeResponse struct {
+	ID       string    `json:"id"`
+	Time     time.Time `json:"time"`
+	Seconds  uint64    `json:"seconds"`
+	Node     uint8     `json:"node"`
+	Sequence uint16    `json:"sequence"`
+}
+
+type statusResponse struct {
+	Status   string            `json:"status"`
+	Failures map[string]string `json:"failures,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func wantsJSON(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "json"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeText(w http.ResponseWriter, status int, s string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(s + "\n"))
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if wantsJSON(r) {
+		writeJSON(w, status, errorResponse{Error: err.Error()})
+		return
+	}
+	writeText(w, status, err.Error())
+}
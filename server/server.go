@@ -0,0 +1,267 @@
+// Package server exposes a snowflake.Generator over HTTP
+//
+// Endpoints:
+//
+//	GET /id            a single new ID
+//	GET /ids?n=count   count new IDs, capped by the max batch size
+//	GET /decode/{id}   time, node and sequence of an ID
+//	GET /healthz       liveness, always ok while the process serves requests
+//	GET /readyz        readiness, fails while the clock is not monotonic, the generator is unhealthy or a readiness check fails
+//
+// Responses are plain text unless the request asks for json either by ?format=json or by an Accept header
+// containing application/json
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/scarabsoft/go-snowflake"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxBatch is the max number of IDs a single /ids request can ask for
+	DefaultMaxBatch = 1000
+)
+
+var (
+	ErrMaxBatchOutOfRange = errors.New("max batch must be greater than 0")
+)
+
+// ReadinessCheck reports whether the server should receive traffic, e.g. whether the node id lease is still held
+type ReadinessCheck func() error
+
+// Server is a http.Handler issuing IDs of a Generator
+type Server struct {
+	gen      snowflake.Generator
+	layout   snowflake.Layout
+	epoch    uint64
+	encoding snowflake.Encoding
+	maxBatch int
+	checks   map[string]ReadinessCheck
+
+	lock     sync.Mutex
+	clockErr error
+
+	mux *http.ServeMux
+}
+
+type Option func(*Server) error
+
+// WithEncoding sets the encoding of issued and decoded IDs. By default EncodingDecimal
+func WithEncoding(encoding snowflake.Encoding) Option {
+	return func(s *Server) error {
+		s.encoding = encoding
+		return nil
+	}
+}
+
+// WithMaxBatch sets the max number of IDs a single /ids request can ask for. By default DefaultMaxBatch
+func WithMaxBatch(maxBatch int) Option {
+	return func(s *Server) error {
+		if maxBatch <= 0 {
+			return ErrMaxBatchOutOfRange
+		}
+		s.maxBatch = maxBatch
+		return nil
+	}
+}
+
+// WithLayout sets the layout used by /decode, must match the layout of the generator. By default DefaultLayout
+func WithLayout(layout snowflake.Layout) Option {
+	return func(s *Server) error {
+		if err := layout.Validate(); err != nil {
+			return err
+		}
+		s.layout = layout
+		return nil
+	}
+}
+
+// WithEpoch sets the epoch in seconds used by /decode to calculate the time of an ID, must match the clock of the generator.
+// By default 0
+func WithEpoch(epoch uint64) Option {
+	return func(s *Server) error {
+		s.epoch = epoch
+		return nil
+	}
+}
+
+// WithReadinessCheck adds a named check to /readyz
+func WithReadinessCheck(name string, check ReadinessCheck) Option {
+	return func(s *Server) error {
+		s.checks[name] = check
+		return nil
+	}
+}
+
+// New returns a new server issuing IDs of gen and apply the requested options
+func New(gen snowflake.Generator, options ...Option) (*Server, error) {
+	r := &Server{
+		gen:      gen,
+		layout:   snowflake.DefaultLayout,
+		encoding: snowflake.EncodingDecimal,
+		maxBatch: DefaultMaxBatch,
+		checks:   map[string]ReadinessCheck{},
+		mux:      http.NewServeMux(),
+	}
+
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mux.HandleFunc("/id", r.handleID)
+	r.mux.HandleFunc("/ids", r.handleIDs)
+	r.mux.HandleFunc("/decode/", r.handleDecode)
+	r.mux.HandleFunc("/healthz", r.handleHealth)
+	r.mux.HandleFunc("/readyz", r.handleReady)
+	return r, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, r, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleID(w http.ResponseWriter, r *http.Request) {
+	ids, err := s.next(1)
+	if err != nil {
+		writeError(w, r, statusOf(err), err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, idResponse{ID: ids[0]})
+		return
+	}
+	writeText(w, http.StatusOK, ids[0])
+}
+
+func (s *Server) handleIDs(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.URL.Query().Get("n"))
+	if err != nil || n <= 0 {
+		writeError(w, r, http.StatusBadRequest, errors.New("n must be a positive number"))
+		return
+	}
+	if n > s.maxBatch {
+		writeError(w, r, http.StatusBadRequest, fmt.Errorf("n is capped to %d", s.maxBatch))
+		return
+	}
+
+	ids, err := s.next(n)
+	if err != nil {
+		writeError(w, r, statusOf(err), err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, idsResponse{IDs: ids})
+		return
+	}
+	writeText(w, http.StatusOK, strings.Join(ids, "\n"))
+}
+
+func (s *Server) handleDecode(w http.ResponseWriter, r *http.Request) {
+	v, err := s.encoding.Parse(strings.TrimPrefix(r.URL.Path, "/decode/"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	id := snowflake.Codec{Epoch: time.Unix(int64(s.epoch), 0), Layout: s.layout}.Decode(v)
+	resp := decodeResponse{
+		ID:       s.encoding.Format(id.ID()),
+		Time:     id.Time(),
+		Seconds:  id.Seconds(),
+		Node:     id.NodeID(),
+		Sequence: id.Iteration(),
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+	writeText(w, http.StatusOK, fmt.Sprintf("%s time=%s node=%d sequence=%d", resp.ID, resp.Time.Format(time.RFC3339), resp.Node, resp.Sequence))
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, statusResponse{Status: "ok"})
+		return
+	}
+	writeText(w, http.StatusOK, "ok")
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	failures := map[string]string{}
+
+	s.lock.Lock()
+	if s.clockErr != nil {
+		failures["clock"] = s.clockErr.Error()
+	}
+	s.lock.Unlock()
+
+	if reporter, ok := s.gen.(snowflake.HealthReporter); ok {
+		if err := reporter.Health(); err != nil {
+			failures["generator"] = err.Error()
+		}
+	}
+
+	for name, check := range s.checks {
+		if err := check(); err != nil {
+			failures[name] = err.Error()
+		}
+	}
+
+	status, text := http.StatusOK, "ok"
+	if len(failures) > 0 {
+		status, text = http.StatusServiceUnavailable, "unavailable"
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, status, statusResponse{Status: text, Failures: failures})
+		return
+	}
+	writeText(w, status, text)
+}
+
+// next generates n IDs and keeps track of the clock state for the readiness check
+func (s *Server) next(n int) ([]string, error) {
+	r := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		id, err := s.gen.Next()
+		s.observe(err)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, s.encoding.Format(id.ID()))
+	}
+	return r, nil
+}
+
+func (s *Server) observe(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err == nil {
+		s.clockErr = nil
+	} else if errors.Is(err, snowflake.ErrClockNotMonotonic) {
+		s.clockErr = err
+	}
+}
+
+func statusOf(err error) int {
+	if errors.Is(err, snowflake.ErrClockNotMonotonic) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
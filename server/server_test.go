@@ -0,0 +1,231 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/has"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type failingGenerator struct {
+	snowflake.Generator
+	err error
+}
+
+func (f *failingGenerator) Next() (snowflake.ID, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.Generator.Next()
+}
+
+func newTestServer(t *testing.T, options ...Option) (*httptest.Server, *failingGenerator) {
+	gen := &failingGenerator{Generator: snowflake.MustNewGenerator(
+		snowflake.WithClock(snowflaketest.NewManualClock(1647619145)),
+		snowflake.WithNodeID(128),
+	)}
+
+	s, err := New(gen, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewServer(s)
+	t.Cleanup(r.Close)
+	return r, gen
+}
+
+func get(t *testing.T, url string, accept string) (int, string) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestNew(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	_, err := New(snowflake.MustNewGenerator(), WithMaxBatch(0))
+	assert.That(err, is.EqualTo(ErrMaxBatchOutOfRange))
+}
+
+func TestServer_ID(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t)
+		status, body := get(t, s.URL+"/id", "")
+		assert.That(status, is.EqualTo(http.StatusOK))
+		assert.That(body, is.EqualTo("6910615572447233\n"))
+	})
+	t.Run("json", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t, WithEncoding(snowflake.EncodingHex))
+		status, body := get(t, s.URL+"/id", "application/json")
+		assert.That(status, is.EqualTo(http.StatusOK))
+		assert.That(body, is.EqualTo(`{"id":"188d2b12600001"}`+"\n"))
+	})
+	t.Run("clock not monotonic", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, gen := newTestServer(t)
+		gen.err = snowflake.ErrClockNotMonotonic
+		status, body := get(t, s.URL+"/id?format=json", "")
+		assert.That(status, is.EqualTo(http.StatusServiceUnavailable))
+		assert.That(body, is.EqualTo(`{"error":"clock is not monotonic"}`+"\n"))
+	})
+	t.Run("method not allowed", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t)
+		resp, err := http.Post(s.URL+"/id", "text/plain", nil)
+		assert.That(err, is.Nil())
+		_ = resp.Body.Close()
+		assert.That(resp.StatusCode, is.EqualTo(http.StatusMethodNotAllowed))
+	})
+}
+
+func TestServer_IDs(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t)
+		status, body := get(t, s.URL+"/ids?n=3", "")
+		assert.That(status, is.EqualTo(http.StatusOK))
+		assert.That(body, is.EqualTo("6910615572447233\n6910615572447234\n6910615572447235\n"))
+	})
+	t.Run("json", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t)
+		status, body := get(t, s.URL+"/ids?n=2&format=json", "")
+		assert.That(status, is.EqualTo(http.StatusOK))
+
+		var resp idsResponse
+		assert.That(json.Unmarshal([]byte(body), &resp), is.Nil())
+		assert.That(resp.IDs, has.Length(2))
+	})
+	t.Run("exceeds max batch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t, WithMaxBatch(2))
+		status, body := get(t, s.URL+"/ids?n=3", "")
+		assert.That(status, is.EqualTo(http.StatusBadRequest))
+		assert.That(body, is.EqualTo("n is capped to 2\n"))
+	})
+	t.Run("invalid n", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t)
+		for _, n := range []string{"", "0", "-1", "abc"} {
+			status, _ := get(t, s.URL+"/ids?n="+n, "")
+			assert.That(status, is.EqualTo(http.StatusBadRequest))
+		}
+	})
+}
+
+func TestServer_Decode(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t)
+		status, body := get(t, s.URL+"/decode/6910615572447233", "")
+		assert.That(status, is.EqualTo(http.StatusOK))
+		assert.That(body, is.EqualTo("6910615572447233 time=2022-03-18T15:59:05Z node=128 sequence=1\n"))
+	})
+	t.Run("json with encoding and epoch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t, WithEncoding(snowflake.EncodingBase62), WithEpoch(60))
+		status, body := get(t, s.URL+"/decode/VeLGH3LIP", "application/json")
+		assert.That(status, is.EqualTo(http.StatusOK))
+		assert.That(body, is.EqualTo(`{"id":"VeLGH3LIP","time":"2022-03-18T16:00:05Z","seconds":1647619145,"node":128,"sequence":1}`+"\n"))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t)
+		status, _ := get(t, s.URL+"/decode/abc", "")
+		assert.That(status, is.EqualTo(http.StatusBadRequest))
+	})
+}
+
+func TestServer_Health(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	s, gen := newTestServer(t)
+	gen.err = snowflake.ErrClockNotMonotonic
+	status, body := get(t, s.URL+"/healthz", "")
+	assert.That(status, is.EqualTo(http.StatusOK))
+	assert.That(body, is.EqualTo("ok\n"))
+}
+
+func TestServer_Ready(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t)
+		status, body := get(t, s.URL+"/readyz", "")
+		assert.That(status, is.EqualTo(http.StatusOK))
+		assert.That(body, is.EqualTo("ok\n"))
+	})
+	t.Run("clock not monotonic until next success", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, gen := newTestServer(t)
+
+		gen.err = snowflake.ErrClockNotMonotonic
+		_, _ = get(t, s.URL+"/id", "")
+		status, body := get(t, s.URL+"/readyz", "application/json")
+		assert.That(status, is.EqualTo(http.StatusServiceUnavailable))
+		assert.That(body, is.EqualTo(`{"status":"unavailable","failures":{"clock":"clock is not monotonic"}}`+"\n"))
+
+		gen.err = nil
+		_, _ = get(t, s.URL+"/id", "")
+		status, _ = get(t, s.URL+"/readyz", "")
+		assert.That(status, is.EqualTo(http.StatusOK))
+	})
+	t.Run("readiness check", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s, _ := newTestServer(t, WithReadinessCheck("lease", func() error {
+			return errors.New("lease expired")
+		}))
+		status, body := get(t, s.URL+"/readyz?format=json", "")
+		assert.That(status, is.EqualTo(http.StatusServiceUnavailable))
+		assert.That(strings.TrimSpace(body), is.EqualTo(`{"status":"unavailable","failures":{"lease":"lease expired"}}`))
+	})
+	t.Run("unhealthy generator", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+
+		wall := time.Unix(1647619145, 0)
+		monitor, err := snowflake.NewClockMonitor(time.Hour, snowflake.WithMonitorReadings(func() snowflake.ClockReading {
+			wall = wall.Add(-time.Minute)
+			return snowflake.ClockReading{Wall: wall}
+		}))
+		assert.That(err, is.Nil())
+		defer monitor.Close()
+		monitor.Check()
+
+		s, err := New(snowflake.MustNewGenerator(
+			snowflake.WithClock(snowflaketest.NewManualClock(1647619145)),
+			snowflake.WithClockMonitor(monitor),
+		))
+		assert.That(err, is.Nil())
+		r := httptest.NewServer(s)
+		defer r.Close()
+
+		status, body := get(t, r.URL+"/readyz?format=json", "")
+		assert.That(status, is.EqualTo(http.StatusServiceUnavailable))
+		assert.That(body, has.Prefix(`{"status":"unavailable","failures":{"generator":"clock is untrustworthy: clock rollback by -1m0s`))
+	})
+}
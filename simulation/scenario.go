@@ -0,0 +1,245 @@
+package simulation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EventKind is the kind of clock or process disturbance injected into a node
+type EventKind string
+
+const (
+	// Rollback moves the clock of a node backwards by Amount seconds
+	Rollback EventKind = "rollback"
+	// Jump moves the clock of a node forward by Amount seconds
+	Jump EventKind = "jump"
+	// Stall stops the clock of a node for Amount seconds of virtual time
+	Stall EventKind = "stall"
+	// Restart replaces the generator of a node by a new one, losing its sequence state
+	Restart EventKind = "restart"
+)
+
+// Event disturbs a single node at a given second of virtual time
+type Event struct {
+	At     uint64
+	Kind   EventKind
+	Node   int
+	Amount uint64
+}
+
+// Scenario describes a simulation run
+//
+// The textual form consists of one statement per line, # starts a comment:
+//
+//	nodes 4                       number of generators, node ids 0 to nodes-1
+//	duration 60                   virtual seconds to simulate
+//	rate 100                      IDs each node requests per virtual second
+//	max_sequence 16383            max sequence of every generator
+//	skew node=2 by=-3             initial clock offset of a node in seconds
+//	at 10 rollback node=1 by=5    clock of node 1 goes back 5 seconds at second 10
+//	at 20 stall node=2 for=3      clock of node 2 stands still for 3 seconds
+//	at 30 jump node=0 by=10       clock of node 0 goes forward 10 seconds
+//	at 40 restart node=3          generator of node 3 gets recreated
+type Scenario struct {
+	Nodes       int
+	Duration    uint64
+	Rate        int
+	MaxSequence uint16
+	Skew        map[int]int64
+	Events      []Event
+}
+
+// DefaultScenario returns a scenario with 4 undisturbed nodes running 60 seconds at 100 IDs per second
+func DefaultScenario() Scenario {
+	return Scenario{
+		Nodes:       4,
+		Duration:    60,
+		Rate:        100,
+		MaxSequence: 16383,
+		Skew:        map[int]int64{},
+	}
+}
+
+// ParseScenario parses the textual form of a scenario, statements which are not given keep the default.
+// Events keep the order of the input, Run applies them in the order of At
+func ParseScenario(r io.Reader) (Scenario, error) {
+	s := DefaultScenario()
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if err := s.parseStatement(fields); err != nil {
+			return Scenario{}, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Scenario{}, err
+	}
+
+	return s, s.Validate()
+}
+
+// Validate checks that the scenario can be run
+func (s Scenario) Validate() error {
+	if s.Nodes <= 0 || s.Nodes > 256 {
+		return fmt.Errorf("nodes must be between 1 and 256, got %d", s.Nodes)
+	}
+	if s.Rate <= 0 {
+		return fmt.Errorf("rate must be greater than 0, got %d", s.Rate)
+	}
+	if s.MaxSequence == 0 {
+		return fmt.Errorf("max_sequence must be greater than 0")
+	}
+	for node, skew := range s.Skew {
+		if node < 0 || node >= s.Nodes {
+			return fmt.Errorf("skew of unknown node %d", node)
+		}
+		if skew < -startSeconds || skew > math.MaxInt64-startSeconds {
+			return fmt.Errorf("skew of node %d must be between %d and %d, got %d", node, -startSeconds, int64(math.MaxInt64-startSeconds), skew)
+		}
+	}
+	for _, event := range s.Events {
+		if event.Node < 0 || event.Node >= s.Nodes {
+			return fmt.Errorf("%s at %d of unknown node %d", event.Kind, event.At, event.Node)
+		}
+		if event.At >= s.Duration {
+			return fmt.Errorf("%s at %d of node %d is not within the duration of %d seconds", event.Kind, event.At, event.Node, s.Duration)
+		}
+	}
+	return nil
+}
+
+func (s *Scenario) parseStatement(fields []string) error {
+	switch fields[0] {
+	case "nodes":
+		return parseSingle(fields, func(v string) (err error) {
+			s.Nodes, err = strconv.Atoi(v)
+			return
+		})
+	case "duration":
+		return parseSingle(fields, func(v string) (err error) {
+			s.Duration, err = strconv.ParseUint(v, 10, 64)
+			return
+		})
+	case "rate":
+		return parseSingle(fields, func(v string) (err error) {
+			s.Rate, err = strconv.Atoi(v)
+			return
+		})
+	case "max_sequence":
+		return parseSingle(fields, func(v string) error {
+			maxSequence, err := strconv.ParseUint(v, 10, 16)
+			s.MaxSequence = uint16(maxSequence)
+			return err
+		})
+	case "skew":
+		args, err := parseArgs(fields[1:], "node", "by")
+		if err != nil {
+			return err
+		}
+		node, err := strconv.Atoi(args["node"])
+		if err != nil {
+			return fmt.Errorf("node: %w", err)
+		}
+		by, err := strconv.ParseInt(args["by"], 10, 64)
+		if err != nil {
+			return fmt.Errorf("by: %w", err)
+		}
+		s.Skew[node] = by
+		return nil
+	case "at":
+		return s.parseEvent(fields[1:])
+	}
+	return fmt.Errorf("unknown statement %q", fields[0])
+}
+
+func (s *Scenario) parseEvent(fields []string) error {
+	if len(fields) < 2 {
+		return fmt.Errorf("expected: at <second> <event> node=<id> [by|for=<seconds>]")
+	}
+
+	at, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("at: %w", err)
+	}
+
+	event := Event{At: at, Kind: EventKind(fields[1])}
+	var amountKey string
+	switch event.Kind {
+	case Rollback, Jump:
+		amountKey = "by"
+	case Stall:
+		amountKey = "for"
+	case Restart:
+	default:
+		return fmt.Errorf("unknown event %q", fields[1])
+	}
+
+	keys := []string{"node"}
+	if amountKey != "" {
+		keys = append(keys, amountKey)
+	}
+	args, err := parseArgs(fields[2:], keys...)
+	if err != nil {
+		return err
+	}
+
+	if event.Node, err = strconv.Atoi(args["node"]); err != nil {
+		return fmt.Errorf("node: %w", err)
+	}
+	if amountKey != "" {
+		if event.Amount, err = strconv.ParseUint(args[amountKey], 10, 64); err != nil {
+			return fmt.Errorf("%s: %w", amountKey, err)
+		}
+	}
+
+	s.Events = append(s.Events, event)
+	return nil
+}
+
+func parseSingle(fields []string, set func(string) error) error {
+	if len(fields) != 2 {
+		return fmt.Errorf("%s expects exactly one value", fields[0])
+	}
+	if err := set(fields[1]); err != nil {
+		return fmt.Errorf("%s: %w", fields[0], err)
+	}
+	return nil
+}
+
+// parseArgs parses key=value pairs, all keys are required and no other keys are allowed
+func parseArgs(fields []string, keys ...string) (map[string]string, error) {
+	r := map[string]string{}
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("expected key=value, got %q", field)
+		}
+		r[kv[0]] = kv[1]
+	}
+
+	for _, key := range keys {
+		if _, found := r[key]; !found {
+			return nil, fmt.Errorf("missing %s=", key)
+		}
+	}
+	if len(r) != len(keys) {
+		return nil, fmt.Errorf("expected only %s", strings.Join(keys, ", "))
+	}
+	return r, nil
+}
@@ -0,0 +1,67 @@
+package simulation
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"strings"
+	"testing"
+)
+
+func TestParseScenario(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := ParseScenario(strings.NewReader(`
+# fleet
+nodes 3
+duration 50
+rate 10
+max_sequence 100
+skew node=2 by=-3
+
+at 30 jump node=0 by=10
+at 10 rollback node=1 by=5   # goes back
+at 20 stall node=2 for=3
+at 40 restart node=2
+`))
+		assert.That(err, is.Nil())
+		assert.That(r.Nodes, is.EqualTo(3))
+		assert.That(r.Duration, is.EqualTo(uint64(50)))
+		assert.That(r.Rate, is.EqualTo(10))
+		assert.That(r.MaxSequence, is.EqualTo(uint16(100)))
+		assert.That(r.Skew, is.EqualTo(map[int]int64{2: -3}))
+		assert.That(r.Events, is.EqualTo([]Event{
+			{At: 30, Kind: Jump, Node: 0, Amount: 10},
+			{At: 10, Kind: Rollback, Node: 1, Amount: 5},
+			{At: 20, Kind: Stall, Node: 2, Amount: 3},
+			{At: 40, Kind: Restart, Node: 2},
+		}))
+	})
+	t.Run("defaults", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := ParseScenario(strings.NewReader(""))
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo(DefaultScenario()))
+	})
+
+	for name, tc := range map[string]struct {
+		input    string
+		expected string
+	}{
+		"unknown statement": {"foo 1", `line 1: unknown statement "foo"`},
+		"unknown event":     {"\nat 1 explode node=1", `line 2: unknown event "explode"`},
+		"missing argument":  {"at 1 rollback node=1", "line 1: missing by="},
+		"unexpected arg":    {"at 1 restart node=1 by=2", "line 1: expected only node"},
+		"not a number":      {"nodes many", `line 1: nodes: strconv.Atoi: parsing "many": invalid syntax`},
+		"unknown node":      {"nodes 2\nat 1 restart node=2", "restart at 1 of unknown node 2"},
+		"too many nodes":    {"nodes 257", "nodes must be between 1 and 256, got 257"},
+		"after duration":    {"duration 10\nat 10 restart node=1", "restart at 10 of node 1 is not within the duration of 10 seconds"},
+		"skew before zero":  {"skew node=1 by=-1073741825", "skew of node 1 must be between -1073741824 and 9223372035781033983, got -1073741825"},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			assert := hamcrest.NewAssertion(t)
+			_, err := ParseScenario(strings.NewReader(tc.input))
+			assert.That(err.Error(), is.EqualTo(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Package simulation runs a fleet of generators over virtual clocks which get rolled back, stalled, jumped and
+// restarted according to a Scenario and reports duplicates, ordering violations and throughput
+package simulation
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// startSeconds is the virtual time every clock starts at, far enough from 0 to allow rollbacks
+	startSeconds = 1 << 30
+)
+
+// Report summarizes a simulation run
+type Report struct {
+	Nodes          int
+	VirtualSeconds uint64
+	// Requested is the number of IDs the nodes asked for
+	Requested int
+	// Issued is the number of IDs the generators returned
+	Issued int
+	// Exhausted is the number of requests skipped because the sequence of the current second was exhausted
+	Exhausted int
+	// Errors counts the errors returned by the generators by message
+	Errors     map[string]int
+	Violations []snowflaketest.Violation
+	Elapsed    time.Duration
+}
+
+// Duplicates returns the number of IDs which were issued more than once across all nodes
+func (r Report) Duplicates() int {
+	return r.count(snowflaketest.Duplicate)
+}
+
+// OrderViolations returns the number of IDs which were not greater than the previous ID of the same node
+func (r Report) OrderViolations() int {
+	return r.count(snowflaketest.OutOfOrder)
+}
+
+// Throughput returns the issued IDs per second of wall time
+func (r Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Issued) / r.Elapsed.Seconds()
+}
+
+// OK reports whether all issued IDs were unique and ordered per node
+func (r Report) OK() bool {
+	return len(r.Violations) == 0
+}
+
+func (r Report) String() string {
+	b := &strings.Builder{}
+	_, _ = fmt.Fprintf(b, "nodes:            %d\n", r.Nodes)
+	_, _ = fmt.Fprintf(b, "virtual seconds:  %d\n", r.VirtualSeconds)
+	_, _ = fmt.Fprintf(b, "requested:        %d\n", r.Requested)
+	_, _ = fmt.Fprintf(b, "issued:           %d\n", r.Issued)
+	_, _ = fmt.Fprintf(b, "exhausted:        %d\n", r.Exhausted)
+	_, _ = fmt.Fprintf(b, "duplicates:       %d\n", r.Duplicates())
+	_, _ = fmt.Fprintf(b, "order violations: %d\n", r.OrderViolations())
+	_, _ = fmt.Fprintf(b, "throughput:       %.0f ids/s\n", r.Throughput())
+
+	messages := make([]string, 0, len(r.Errors))
+	for msg := range r.Errors {
+		messages = append(messages, msg)
+	}
+	sort.Strings(messages)
+	for _, msg := range messages {
+		_, _ = fmt.Fprintf(b, "error:            %d x %s\n", r.Errors[msg], msg)
+	}
+	return b.String()
+}
+
+func (r Report) count(kind snowflaketest.ViolationKind) int {
+	n := 0
+	for _, v := range r.Violations {
+		if v.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// restartableGenerator keeps its identity for the uniqueness checker while the wrapped generator gets replaced
+type restartableGenerator struct {
+	gen snowflake.Generator
+}
+
+func (r *restartableGenerator) Next() (snowflake.ID, error) {
+	return r.gen.Next()
+}
+
+func (r *restartableGenerator) MustNext() snowflake.ID {
+	return r.gen.MustNext()
+}
+
+type node struct {
+	id          uint8
+	maxSequence uint16
+	clock       *snowflaketest.ManualClock
+	current     *restartableGenerator
+	checked     snowflake.Generator
+	stall       uint64
+
+	// last sequence issued by the current generator, used to skip requests which would block on exhaustion
+	hasLast       bool
+	lastSeconds   uint64
+	lastIteration uint16
+}
+
+func (n *node) restart() error {
+	gen, err := snowflake.NewGenerator(
+		snowflake.WithClock(n.clock),
+		snowflake.WithNodeIDProvider(snowflake.NewFixedNodeProvider(n.id)),
+		snowflake.WithMaxSequence(n.maxSequence),
+	)
+	if err != nil {
+		return err
+	}
+	n.current.gen = gen
+	n.hasLast = false
+	return nil
+}
+
+// exhausted reports whether the next request would wait for the clock to make progress
+func (n *node) exhausted() bool {
+	return n.hasLast && n.lastSeconds == n.clock.Seconds() && n.lastIteration >= n.maxSequence
+}
+
+// Run simulates the scenario and returns its report, the events are applied in the order of At
+func Run(s Scenario) (Report, error) {
+	if err := s.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	checker := snowflaketest.NewUniquenessChecker()
+	nodes := make([]*node, s.Nodes)
+	for i := range nodes {
+		n := &node{
+			id:          uint8(i),
+			maxSequence: s.MaxSequence,
+			clock:       snowflaketest.NewManualClock(uint64(startSeconds + s.Skew[i])),
+			current:     &restartableGenerator{},
+		}
+		n.checked = checker.Attach(n.current)
+		if err := n.restart(); err != nil {
+			return Report{}, err
+		}
+		nodes[i] = n
+	}
+
+	r := Report{Nodes: s.Nodes, VirtualSeconds: s.Duration, Errors: map[string]int{}}
+	events := append([]Event(nil), s.Events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At < events[j].At
+	})
+	started := time.Now()
+
+	for second := uint64(0); second < s.Duration; second++ {
+		for len(events) > 0 && events[0].At == second {
+			if err := apply(nodes[events[0].Node], events[0]); err != nil {
+				return Report{}, err
+			}
+			events = events[1:]
+		}
+
+		for _, n := range nodes {
+			for i := 0; i < s.Rate; i++ {
+				r.Requested++
+				if n.exhausted() {
+					r.Exhausted++
+					continue
+				}
+
+				id, err := n.checked.Next()
+				if err != nil {
+					r.Errors[err.Error()]++
+					continue
+				}
+
+				r.Issued++
+				n.hasLast, n.lastSeconds, n.lastIteration = true, id.Seconds(), id.Iteration()
+			}
+		}
+
+		for _, n := range nodes {
+			if n.stall > 0 {
+				n.stall--
+				continue
+			}
+			n.clock.Advance(1)
+		}
+	}
+
+	r.Elapsed = time.Since(started)
+	r.Violations = checker.Violations()
+	return r, nil
+}
+
+func apply(n *node, event Event) error {
+	switch event.Kind {
+	case Rollback:
+		n.clock.Rewind(event.Amount)
+	case Jump:
+		n.clock.Advance(event.Amount)
+	case Stall:
+		n.stall = event.Amount
+	case Restart:
+		return n.restart()
+	}
+	return nil
+}
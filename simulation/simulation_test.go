@@ -0,0 +1,87 @@
+package simulation
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/has"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) Scenario {
+	r, err := ParseScenario(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRun(t *testing.T) {
+	t.Run("undisturbed", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := Run(mustParse(t, "nodes 4\nduration 10\nrate 100\nskew node=1 by=-2\nskew node=3 by=5"))
+		assert.That(err, is.Nil())
+		assert.That(r.OK(), is.True())
+		assert.That(r.Requested, is.EqualTo(4000))
+		assert.That(r.Issued, is.EqualTo(4000))
+		assert.That(len(r.Errors), is.EqualTo(0))
+	})
+	t.Run("rollback", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := Run(mustParse(t, "nodes 2\nduration 10\nrate 10\nat 3 rollback node=1 by=2"))
+		assert.That(err, is.Nil())
+		assert.That(r.OK(), is.True())
+		assert.That(r.Errors, is.EqualTo(map[string]int{snowflake.ErrClockNotMonotonic.Error(): 10}))
+		assert.That(r.Issued, is.EqualTo(190))
+	})
+	t.Run("jump", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := Run(mustParse(t, "nodes 2\nduration 10\nrate 10\nat 3 jump node=0 by=100"))
+		assert.That(err, is.Nil())
+		assert.That(r.OK(), is.True())
+		assert.That(r.Issued, is.EqualTo(200))
+	})
+	t.Run("stall exhausts sequence", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := Run(mustParse(t, "nodes 2\nduration 10\nrate 10\nmax_sequence 15\nat 3 stall node=0 for=2"))
+		assert.That(err, is.Nil())
+		assert.That(r.OK(), is.True())
+		assert.That(r.Exhausted, is.EqualTo(15))
+		assert.That(r.Issued, is.EqualTo(185))
+	})
+	t.Run("unsorted events", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s := mustParse(t, "nodes 2\nduration 10\nrate 10")
+		s.Events = []Event{{At: 5, Kind: Jump, Node: 0, Amount: 100}, {At: 3, Kind: Rollback, Node: 1, Amount: 2}}
+
+		r, err := Run(s)
+		assert.That(err, is.Nil())
+		assert.That(r.Errors, is.EqualTo(map[string]int{snowflake.ErrClockNotMonotonic.Error(): 10}))
+		assert.That(s.Events[0].At, is.EqualTo(uint64(5)))
+	})
+	t.Run("event after duration", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		s := mustParse(t, "nodes 2\nduration 10\nrate 10")
+		s.Events = []Event{{At: 10, Kind: Restart, Node: 0}}
+
+		_, err := Run(s)
+		assert.That(err.Error(), is.EqualTo("restart at 10 of node 0 is not within the duration of 10 seconds"))
+	})
+	t.Run("restart within the same second", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := Run(mustParse(t, "nodes 2\nduration 10\nrate 10\nat 5 stall node=1 for=1\nat 6 restart node=1"))
+		assert.That(err, is.Nil())
+		assert.That(r.OK(), is.False())
+		assert.That(r.Duplicates(), is.EqualTo(10))
+		assert.That(r.OrderViolations(), is.EqualTo(1))
+	})
+}
+
+func TestReport_String(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	r, err := Run(mustParse(t, "nodes 1\nduration 2\nrate 1\nat 1 rollback node=0 by=2"))
+	assert.That(err, is.Nil())
+	assert.That(r.String(), has.Prefix("nodes:            1\nvirtual seconds:  2\nrequested:        2\nissued:           1\n"))
+	assert.That(r.String(), has.Suffix("error:            1 x clock is not monotonic\n"))
+}
@@ -3,8 +3,10 @@ package snowflake
 import (
 	"fmt"
 	"github.com/scarabsoft/go-snowflake/internal"
-	"os"
-	"strconv"
+	"math"
+	"strings"
+	"sync"
+	"time"
 )
 
 type NodeIDProvider interface {
@@ -24,17 +26,29 @@ type Generator interface {
 }
 
 type generatorImpl struct {
-	gen internal.SnowflakeGenerator
+	gen     internal.SnowflakeGenerator
+	codec   Codec
+	monitor *ClockMonitor
+	// hlc drives generators of NewHLCGenerator
+	hlc HybridLogicalClock
 }
 
 type idImpl struct {
-	id uint64
+	id     uint64
+	layout internal.Layout
+	epoch  uint64
+	fields []Field
 }
 
 func (i idImpl) ID() uint64 {
 	return i.id
 }
 
+// Int64 returns the ID as signed integer, it is negative if the sign bit is set
+func (i idImpl) Int64() int64 {
+	return int64(i.id)
+}
+
 func (i idImpl) Weeks() uint64 {
 	return i.Days() / 7
 }
@@ -52,15 +66,32 @@ func (i idImpl) Minutes() uint64 {
 }
 
 func (i idImpl) Seconds() uint64 {
-	return i.ID() >> 22
+	seconds, _, _ := i.layout.Decode(i.id)
+	return seconds
 }
 
 func (i idImpl) NodeID() uint8 {
-	return uint8(i.ID() >> (14))
+	_, nodeID, _ := i.layout.Decode(i.id)
+	return nodeID
+}
+
+func (i idImpl) Node() uint16 {
+	return uint16(i.NodeID())
 }
 
 func (i idImpl) Iteration() uint16 {
-	return uint16(i.ID())
+	_, _, iteration := i.layout.Decode(i.id)
+	return iteration
+}
+
+// Field returns the value of the named field within the node bits, false if the ID has no such field
+func (i idImpl) Field(name string) (uint8, bool) {
+	return internal.FieldValue(i.fields, name, i.NodeID())
+}
+
+// Time returns the second the ID was generated at, based on the epoch of the generator or codec it came from
+func (i idImpl) Time() time.Time {
+	return time.Unix(int64(i.epoch+i.Seconds()), 0).UTC()
 }
 
 func (i idImpl) String() string {
@@ -81,12 +112,34 @@ type ID interface {
 	String() string
 }
 
+// ExtendedID is implemented by every ID of this package. It is separate from ID so implementations of ID outside
+// this package keep working, IDs returned as ID can be asserted to it
+type ExtendedID interface {
+	ID
+	Int64() int64
+	// Node returns the node id in its full width, NodeID truncates nodes of variants wider than 8 bits
+	Node() uint16
+	Field(name string) (uint8, bool)
+	Time() time.Time
+}
+
 func (g *generatorImpl) Next() (ID, error) {
 	r, err := g.gen.Next()
 	if err != nil {
 		return nil, err
 	}
-	return &idImpl{r}, nil
+	if g.codec.SignedSafe && r > math.MaxInt64 {
+		return nil, ErrSignBitReached
+	}
+	return g.codec.Decode(r), nil
+}
+
+// Health returns the health of the clock monitor, nil without monitor
+func (g *generatorImpl) Health() error {
+	if g.monitor == nil {
+		return nil
+	}
+	return g.monitor.Healthy()
 }
 
 func (g *generatorImpl) MustNext() ID {
@@ -110,10 +163,64 @@ func NewUnixClockWithEpoch(epoch uint64) Clock {
 	return internal.NewUnixClockWithEpoch(epoch)
 }
 
+// CoarseClock is a Clock reading a cached time instead of calling time.Now for every ID, a background ticker
+// refreshes the time every resolution and reads fall back to time.Now if it lags behind. Now can be passed to
+// WithVariantNow. It has to be stopped by invoking Close(), afterwards it reads time.Now
+type CoarseClock interface {
+	Clock
+	Now() time.Time
+	Close() error
+}
+
+// NewCoarseClock returns a coarse clock counting from the UNIX epoch, resolutions <= 0 fall back to 1ms
+func NewCoarseClock(resolution time.Duration) CoarseClock {
+	return NewCoarseClockWithEpoch(0, resolution)
+}
+
+// NewCoarseClockWithEpoch returns a coarse clock counting from epoch in seconds since the UNIX epoch
+func NewCoarseClockWithEpoch(epoch uint64, resolution time.Duration) CoarseClock {
+	if resolution <= 0 {
+		resolution = time.Millisecond
+	}
+	return internal.NewCoarseClockWithEpoch(epoch, resolution)
+}
+
+// Sequence is the timestamp and iteration of a single ID or the error which prevented generating it
+type Sequence = internal.Sequence
+
+// SequenceProvider provides the timestamp and iteration for every ID, it must never return the same sequence twice
+type SequenceProvider interface {
+	internal.SequenceProvider
+}
+
+// Field is a named part of the node bits, like a region, a worker or an entity type tag
+type Field = internal.Field
+
 type generatorBuilderImpl struct {
-	clock        Clock
-	nodeProvider NodeIDProvider
-	maxSequence  uint16
+	clock          Clock
+	nodeProvider   NodeIDProvider
+	maxSequence    uint16
+	layout         Layout
+	seqProvider    SequenceProvider
+	fields         []Field
+	fieldValues    []uint8
+	signedSafe     bool
+	startupGuard   bool
+	tickMarker     TickMarker
+	maxStartupWait time.Duration
+	registryMode   RegistryMode
+	nodeIDs        []uint8
+	spareNodeIDs   []uint8
+	monitor        *ClockMonitor
+	// uncertaintyGuard is set by WithMaxUncertainty
+	uncertaintyGuard bool
+	maxUncertainty   time.Duration
+	uncertaintyWait  time.Duration
+	pacingBurst      int
+	randomStart      bool
+	// hybrid is set by NewHLCGenerator, build stores the created clock in hlc
+	hybrid bool
+	hlc    HybridLogicalClock
 }
 
 type Option func(*generatorBuilderImpl) error
@@ -126,6 +233,18 @@ func WithClock(clock Clock) Option {
 	}
 }
 
+// WithEpoch sets a system clock counting from epoch, replaces the clock set by WithClock.
+// The epoch is truncated to seconds. By default the UNIX epoch
+func WithEpoch(epoch time.Time) Option {
+	return func(impl *generatorBuilderImpl) error {
+		if epoch.Unix() < 0 {
+			return ErrEpochBeforeUnixEpoch
+		}
+		impl.clock = NewUnixClockWithEpoch(uint64(epoch.Unix()))
+		return nil
+	}
+}
+
 // WithNodeIDProvider sets the NodeIDProvider, which allows generating nodeID based on hardware, like MAC or ...
 // Make sure it generates a unique 8bit ID per node otherwise you will get duplicated IDs
 func WithNodeIDProvider(provider NodeIDProvider) Option {
@@ -143,7 +262,75 @@ func WithNodeID(nodeID uint8) Option {
 	}
 }
 
+// WithNodeIDs lets the generator own several node ids, once the sequence of a node is exhausted within a tick the
+// generator continues with the sequence of the next node instead of waiting for the next tick. IDs of every node are
+// monotonic, ascending node ids keep all IDs monotonic. Replaces the node set by WithNodeID or WithNodeIDProvider
+func WithNodeIDs(ids ...uint8) Option {
+	return func(impl *generatorBuilderImpl) error {
+		if len(ids) == 0 {
+			return ErrNodeIDsInvalid
+		}
+		impl.nodeIDs = append([]uint8(nil), ids...)
+		return nil
+	}
+}
+
+// WithSpareNodeIDs reserves node ids which take over while the clock is behind the last tick of the node, instead of
+// failing with ErrClockNotMonotonic. The generator switches back once the clock caught up. The spare node ids must be
+// as unique as the node id, every spare covers one rollback and fails once the clock goes back behind its own last tick
+func WithSpareNodeIDs(ids ...uint8) Option {
+	return func(impl *generatorBuilderImpl) error {
+		impl.spareNodeIDs = append([]uint8(nil), ids...)
+		return nil
+	}
+}
+
+// WithField splits the node bits into named fields, fields are stored in the order of the options from most to least
+// significant and must use exactly the node bits of the layout. The node id is composed of the field values and replaces
+// the node set by WithNodeID or WithNodeIDProvider
+func WithField(name string, bits uint8, value uint8) Option {
+	return func(impl *generatorBuilderImpl) error {
+		field := Field{Name: name, Bits: bits}
+		if value > field.MaxValue() {
+			return fmt.Errorf("%w: %s=%d exceeds %d bits", ErrFieldValueOutOfRange, name, value, bits)
+		}
+		impl.fields = append(impl.fields, field)
+		impl.fieldValues = append(impl.fieldValues, value)
+		return nil
+	}
+}
+
+// WithSignedSafe reserves the sign bit, so IDs stay positive when stored as signed 64 bit integer like a BIGINT.
+// The top timestamp bit is lost, Next returns ErrSignBitReached once the timestamp would need it
+func WithSignedSafe() Option {
+	return func(impl *generatorBuilderImpl) error {
+		impl.signedSafe = true
+		return nil
+	}
+}
+
+// WithLayout sets how the bits of an ID are split between timestamp, node and sequence. By default DefaultLayout
+func WithLayout(layout Layout) Option {
+	return func(impl *generatorBuilderImpl) error {
+		if err := layout.Validate(); err != nil {
+			return err
+		}
+		impl.layout = layout
+		return nil
+	}
+}
+
+// WithSequenceProvider replaces the sequence provider driven by the clock, WithClock and WithMaxSequence have no effect
+// Mainly useful for tests which need full control over the generated sequences
+func WithSequenceProvider(provider SequenceProvider) Option {
+	return func(impl *generatorBuilderImpl) error {
+		impl.seqProvider = provider
+		return nil
+	}
+}
+
 // WithMaxSequence sets the max sequence per s the system should support. By default, 16,383 (16,383 ids can be generated per s)
+// or the max sequence of the layout. 0 falls back to the default
 func WithMaxSequence(maxSeq uint16) Option {
 	return func(impl *generatorBuilderImpl) error {
 		impl.maxSequence = maxSeq
@@ -151,47 +338,205 @@ func WithMaxSequence(maxSeq uint16) Option {
 	}
 }
 
+// WithRandomSequenceStart starts the iterations of every second at a random offset instead of at 1, so Iteration()
+// does not reveal how many IDs were issued within a second. The offsets come from a source seeded by crypto/rand and
+// wrap around within the max sequence, a second keeps its full budget of unique IDs. In exchange IDs of the same
+// second are no longer ordered by issue time. It has no effect with WithSequenceProvider
+func WithRandomSequenceStart() Option {
+	return func(impl *generatorBuilderImpl) error {
+		impl.randomStart = true
+		return nil
+	}
+}
+
 // NewGenerator returns a new default generator and apply the requested options
 //
 // Default:
 //		- Clock: system clock returning UNIX epoch
 //		- Node: has ID 1
 //		- MaxSequence: set to 16,383 (16,383 ids can be generated per s)
+//		- Layout: DefaultLayout, 42 timestamp bits, 8 node bits and 14 sequence bits
 func NewGenerator(options ...Option) (Generator, error) {
-	r := &generatorBuilderImpl{
-		clock:        NewUnixClock(),
-		nodeProvider: NewFixedNodeProvider(1),
-		maxSequence:  internal.MaxSequence,
+	r, err := newGeneratorBuilder(options...)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, option := range options {
-		if err := option(r); err != nil {
+	if r.registryMode != 0 {
+		gen, err := newRegisteredGenerator(r)
+		if err != nil {
 			return nil, err
 		}
+		return gen, nil
 	}
 
-	seqProvider, err := internal.NewSequenceProvider(
-		r.clock,
-		r.maxSequence,
-	)
+	gen, err := newGenerator(r)
 	if err != nil {
 		return nil, err
 	}
+	return gen, nil
+}
 
-	gen, err := internal.NewGenerator(
-		seqProvider,
-		r.nodeProvider,
-	)
-
+func newGenerator(r *generatorBuilderImpl) (*generatorImpl, error) {
+	gen, err := r.build()
 	if err != nil {
 		return nil, err
 	}
 
+	if r.seqProvider == nil && r.startupGuard {
+		if err := guardStartup(r.clock, r.tickMarker, r.maxStartupWait); err != nil {
+			return nil, err
+		}
+	}
+
 	return &generatorImpl{
-		gen: gen,
+		gen:     gen,
+		codec:   r.codec(),
+		monitor: r.monitor,
+		hlc:     r.hlc,
 	}, nil
 }
 
+// build returns the internal generator, driven by the clock unless a custom sequence provider is set
+func (g *generatorBuilderImpl) build() (internal.SnowflakeGenerator, error) {
+	if g.seqProvider != nil {
+		return internal.NewGenerator(g.seqProvider, g.nodeProvider, g.layout.internal())
+	}
+
+	if err := internal.CheckEpoch(g.clock); err != nil {
+		return nil, err
+	}
+
+	if g.signedSafe && g.clock.Seconds() > g.codec().maxTimestamp() {
+		return nil, ErrSignBitReached
+	}
+
+	maxSequence := g.maxSequence
+	if maxSequence == 0 {
+		maxSequence = g.layout.internal().MaxSequence()
+	}
+
+	var start func(uint16) uint16
+	if g.randomStart {
+		var err error
+		if start, err = internal.NewRandomStart(); err != nil {
+			return nil, err
+		}
+	}
+
+	var gen internal.SnowflakeGenerator
+	switch {
+	case g.hybrid:
+		if len(g.nodeIDs) > 0 || len(g.spareNodeIDs) > 0 || start != nil {
+			return nil, ErrHybridOptionInvalid
+		}
+		hlc, err := NewHybridLogicalClock(g.clock, g.nodeProvider.ID(), maxSequence, g.layout)
+		if err != nil {
+			return nil, err
+		}
+		g.hlc = hlc
+		if gen, err = g.singleNode(hlc, maxSequence); err != nil {
+			return nil, err
+		}
+	case len(g.nodeIDs) > 0 || len(g.spareNodeIDs) > 0:
+		if g.pacingBurst > 0 {
+			return nil, ErrPacingInvalid
+		}
+		multi, err := internal.NewMultiNodeGenerator(g.clock, maxSequence, g.primaryNodeIDs(), g.spareNodeIDs, g.layout.internal())
+		if err != nil {
+			return nil, err
+		}
+		if start != nil {
+			multi.SetStart(start)
+		}
+		gen = multi
+	default:
+		provider, err := internal.NewSequenceProvider(g.clock, maxSequence, g.layout.internal())
+		if err != nil {
+			return nil, err
+		}
+		if start != nil {
+			provider.SetStart(start)
+		}
+		if gen, err = g.singleNode(provider, maxSequence); err != nil {
+			return nil, err
+		}
+	}
+
+	if g.tickMarker != nil {
+		gen = &markingGeneratorImpl{gen: gen, layout: g.layout.internal(), marker: g.tickMarker}
+	}
+	if g.uncertaintyGuard {
+		clock, ok := g.clock.(CorrectedClock)
+		if !ok {
+			return nil, ErrUncertaintyUnavailable
+		}
+		gen = &uncertainGeneratorImpl{gen: gen, clock: clock, threshold: g.maxUncertainty, wait: g.uncertaintyWait}
+	}
+	return gen, nil
+}
+
+// singleNode returns the generator of the node provider driven by seqProvider, paced if WithPacing is set
+func (g *generatorBuilderImpl) singleNode(seqProvider internal.SequenceProvider, maxSequence uint16) (internal.SnowflakeGenerator, error) {
+	if g.pacingBurst > 0 {
+		seqProvider = internal.NewPacedSequenceProvider(seqProvider, g.clock, time.Second, maxSequence, g.pacingBurst, internal.SleepContext)
+	}
+	return internal.NewGenerator(seqProvider, g.nodeProvider, g.layout.internal())
+}
+
+func newGeneratorBuilder(options ...Option) (*generatorBuilderImpl, error) {
+	r := &generatorBuilderImpl{
+		clock:          NewUnixClock(),
+		nodeProvider:   NewFixedNodeProvider(1),
+		layout:         DefaultLayout,
+		maxStartupWait: DefaultMaxStartupWait,
+	}
+
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(r.fields) > 0 {
+		if err := internal.ValidateFields(r.fields, r.layout.NodeBits); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, formatFields(r.fields, r.layout))
+		}
+		nodeID, err := internal.ComposeNodeID(r.fields, r.fieldValues)
+		if err != nil {
+			return nil, err
+		}
+		r.nodeProvider = NewFixedNodeProvider(nodeID)
+	}
+	return r, nil
+}
+
+// formatFields describes fields and the node bits they have to fill, e.g. region:3,worker:4 of 8 node bits
+func formatFields(fields []Field, layout Layout) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = fmt.Sprintf("%s:%d", field.Name, field.Bits)
+	}
+	return fmt.Sprintf("%s of %d node bits", strings.Join(parts, ","), layout.NodeBits)
+}
+
+// primaryNodeIDs returns the node ids set by WithNodeIDs or the single node of the node provider
+func (g *generatorBuilderImpl) primaryNodeIDs() []uint8 {
+	if len(g.nodeIDs) > 0 {
+		return g.nodeIDs
+	}
+	return []uint8{g.nodeProvider.ID()}
+}
+
+// codec returns the codec matching the layout and the epoch of the clock, clocks without epoch count from the UNIX epoch
+func (g *generatorBuilderImpl) codec() Codec {
+	r := Codec{Epoch: time.Unix(0, 0).UTC(), Layout: g.layout, Fields: g.fields, SignedSafe: g.signedSafe}
+	if c, ok := g.clock.(internal.EpochClock); ok {
+		r.Epoch = time.Unix(int64(c.Epoch()), 0).UTC()
+	}
+	return r
+}
+
 func MustNewGenerator(options ...Option) Generator {
 	if r, err := NewGenerator(options...); err != nil {
 		panic(err)
@@ -200,34 +545,32 @@ func MustNewGenerator(options ...Option) Generator {
 	}
 }
 
-func NewID() (ID, error) {
-	nodeID := uint8(1)
-	genesisEpoch := uint64(0)
-
-	if nodeIDStr, found := os.LookupEnv("NODE_ID"); found {
-		if v, err := strconv.Atoi(nodeIDStr); err != nil {
-			panic(err)
-		} else {
-			nodeID = uint8(v)
-		}
-	}
+var defaultGenerator struct {
+	once sync.Once
+	gen  Generator
+	err  error
+}
 
-	if genesisEpochSecondsStr, found := os.LookupEnv("GENESIS_EPOCH_SECONDS"); found {
-		if v, err := strconv.Atoi(genesisEpochSecondsStr); err != nil {
-			panic(err)
-		} else {
-			genesisEpoch = uint64(v)
+// DefaultGenerator returns the process wide generator configured by ConfigFromEnv, it gets created on the first call
+func DefaultGenerator() (Generator, error) {
+	defaultGenerator.once.Do(func() {
+		config, err := ConfigFromEnv()
+		if err != nil {
+			defaultGenerator.err = err
+			return
 		}
-	}
+		defaultGenerator.gen, defaultGenerator.err = NewGeneratorFromConfig(config)
+	})
+	return defaultGenerator.gen, defaultGenerator.err
+}
 
-	if gen, err := NewGenerator(
-		WithNodeID(nodeID),
-		WithClock(NewUnixClockWithEpoch(genesisEpoch)),
-	); err != nil {
+// NewID returns the next ID of the DefaultGenerator
+func NewID() (ID, error) {
+	gen, err := DefaultGenerator()
+	if err != nil {
 		return nil, err
-	} else {
-		return gen.Next()
 	}
+	return gen.Next()
 }
 
 func MustNewID() ID {
@@ -238,6 +581,7 @@ func MustNewID() ID {
 	}
 }
 
+// From decodes an ID which was generated with the DefaultLayout
 func From(id uint64) ID {
-	return &idImpl{id}
+	return DefaultLayout.From(id)
 }
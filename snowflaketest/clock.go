@@ -0,0 +1,49 @@
+// Package snowflaketest provides deterministic clocks, scripted sequence providers and checkers
+// for tests of code using snowflake generators
+package snowflaketest
+
+import (
+	"sync"
+)
+
+// ManualClock is a snowflake.Clock which only moves when told to, it is safe for concurrent use
+type ManualClock struct {
+	lock    sync.Mutex
+	seconds uint64
+}
+
+// NewManualClock returns a clock standing still at seconds
+func NewManualClock(seconds uint64) *ManualClock {
+	return &ManualClock{seconds: seconds}
+}
+
+func (m *ManualClock) Seconds() uint64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.seconds
+}
+
+// Set moves the clock to seconds, which can be before the current time to simulate a clock rollback
+func (m *ManualClock) Set(seconds uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.seconds = seconds
+}
+
+// Advance moves the clock forward by seconds
+func (m *ManualClock) Advance(seconds uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.seconds += seconds
+}
+
+// Rewind moves the clock backwards by seconds, stopping at 0
+func (m *ManualClock) Rewind(seconds uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if seconds > m.seconds {
+		m.seconds = 0
+		return
+	}
+	m.seconds -= seconds
+}
@@ -0,0 +1,26 @@
+package snowflaketest
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"testing"
+)
+
+func TestManualClock(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	testInstance := NewManualClock(10)
+	assert.That(testInstance.Seconds(), is.EqualTo(uint64(10)))
+
+	testInstance.Advance(5)
+	assert.That(testInstance.Seconds(), is.EqualTo(uint64(15)))
+
+	testInstance.Rewind(3)
+	assert.That(testInstance.Seconds(), is.EqualTo(uint64(12)))
+
+	testInstance.Set(100)
+	assert.That(testInstance.Seconds(), is.EqualTo(uint64(100)))
+
+	testInstance.Rewind(1000)
+	assert.That(testInstance.Seconds(), is.EqualTo(uint64(0)))
+}
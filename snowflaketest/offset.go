@@ -0,0 +1,51 @@
+package snowflaketest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeOffsetSource is a snowflake.OffsetSource returning the offset it was told to, it is safe for concurrent use
+type FakeOffsetSource struct {
+	lock        sync.Mutex
+	offset      time.Duration
+	uncertainty time.Duration
+	err         error
+	reads       int
+}
+
+// NewFakeOffsetSource returns a source reporting offset and uncertainty
+func NewFakeOffsetSource(offset, uncertainty time.Duration) *FakeOffsetSource {
+	return &FakeOffsetSource{offset: offset, uncertainty: uncertainty}
+}
+
+func (f *FakeOffsetSource) Offset() (time.Duration, time.Duration, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.reads++
+	if f.err != nil {
+		return 0, 0, f.err
+	}
+	return f.offset, f.uncertainty, nil
+}
+
+// Set changes the reported offset and uncertainty and clears a failure
+func (f *FakeOffsetSource) Set(offset, uncertainty time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.offset, f.uncertainty, f.err = offset, uncertainty, nil
+}
+
+// Fail makes every following read fail with err until Set is called
+func (f *FakeOffsetSource) Fail(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.err = err
+}
+
+// Reads returns how often the source was read
+func (f *FakeOffsetSource) Reads() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.reads
+}
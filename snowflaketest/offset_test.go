@@ -0,0 +1,31 @@
+package snowflaketest
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"testing"
+	"time"
+)
+
+func TestFakeOffsetSource(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	testInstance := NewFakeOffsetSource(time.Second, time.Millisecond)
+	offset, uncertainty, err := testInstance.Offset()
+	assert.That(err, is.Nil())
+	assert.That(offset, is.EqualTo(time.Second))
+	assert.That(uncertainty, is.EqualTo(time.Millisecond))
+
+	failure := errors.New("agent down")
+	testInstance.Fail(failure)
+	_, _, err = testInstance.Offset()
+	assert.That(err, is.EqualTo(failure))
+
+	testInstance.Set(-time.Second, 2*time.Millisecond)
+	offset, uncertainty, err = testInstance.Offset()
+	assert.That(err, is.Nil())
+	assert.That(offset, is.EqualTo(-time.Second))
+	assert.That(uncertainty, is.EqualTo(2*time.Millisecond))
+	assert.That(testInstance.Reads(), is.EqualTo(3))
+}
@@ -0,0 +1,63 @@
+package snowflaketest
+
+import (
+	"github.com/scarabsoft/go-snowflake"
+	"sync"
+)
+
+// RecordingGenerator wraps a snowflake.Generator and records every ID and error it returns
+type RecordingGenerator struct {
+	gen snowflake.Generator
+
+	lock   sync.Mutex
+	ids    []snowflake.ID
+	errors []error
+}
+
+// NewRecordingGenerator returns a generator recording the results of gen
+func NewRecordingGenerator(gen snowflake.Generator) *RecordingGenerator {
+	return &RecordingGenerator{gen: gen}
+}
+
+func (r *RecordingGenerator) Next() (snowflake.ID, error) {
+	id, err := r.gen.Next()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if err != nil {
+		r.errors = append(r.errors, err)
+	} else {
+		r.ids = append(r.ids, id)
+	}
+	return id, err
+}
+
+func (r *RecordingGenerator) MustNext() snowflake.ID {
+	if id, err := r.Next(); err != nil {
+		panic(err)
+	} else {
+		return id
+	}
+}
+
+// IDs returns all successfully generated IDs in the order they were returned
+func (r *RecordingGenerator) IDs() []snowflake.ID {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]snowflake.ID(nil), r.ids...)
+}
+
+// Errors returns all errors in the order they were returned
+func (r *RecordingGenerator) Errors() []error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]error(nil), r.errors...)
+}
+
+// Reset forgets all recorded IDs and errors
+func (r *RecordingGenerator) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.ids = nil
+	r.errors = nil
+}
@@ -0,0 +1,32 @@
+package snowflaketest
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/has"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"testing"
+)
+
+func TestRecordingGenerator(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	testInstance := NewRecordingGenerator(snowflake.MustNewGenerator(
+		snowflake.WithSequenceProvider(NewScriptedSequenceProvider(Ok(10, 1), Ok(10, 2))),
+	))
+
+	assert.That(testInstance.MustNext().Iteration(), is.EqualTo(uint16(1)))
+	assert.That(testInstance.MustNext().Iteration(), is.EqualTo(uint16(2)))
+	_, err := testInstance.Next()
+	assert.That(err, is.EqualTo(ErrScriptExhausted))
+
+	ids := testInstance.IDs()
+	assert.That(ids, has.Length(2))
+	assert.That(ids[0].Iteration(), is.EqualTo(uint16(1)))
+	assert.That(ids[1].Iteration(), is.EqualTo(uint16(2)))
+	assert.That(testInstance.Errors(), is.EqualTo([]error{ErrScriptExhausted}))
+
+	testInstance.Reset()
+	assert.That(len(testInstance.IDs()), is.EqualTo(0))
+	assert.That(len(testInstance.Errors()), is.EqualTo(0))
+}
@@ -0,0 +1,60 @@
+package snowflaketest
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-snowflake"
+	"sync"
+)
+
+var (
+	ErrScriptExhausted = errors.New("all scripted sequences have been consumed")
+)
+
+// Ok returns a successful sequence
+func Ok(seconds uint64, iteration uint16) snowflake.Sequence {
+	return snowflake.Sequence{Seconds: seconds, Iteration: iteration}
+}
+
+// Fail returns a sequence carrying err
+func Fail(err error) snowflake.Sequence {
+	return snowflake.Sequence{Error: err}
+}
+
+// ScriptedSequenceProvider is a snowflake.SequenceProvider returning a fixed list of sequences in order,
+// afterwards every sequence fails with ErrScriptExhausted. Use it with snowflake.WithSequenceProvider
+type ScriptedSequenceProvider struct {
+	lock      sync.Mutex
+	sequences []snowflake.Sequence
+	consumed  int
+}
+
+// NewScriptedSequenceProvider returns a provider returning sequences in the given order
+func NewScriptedSequenceProvider(sequences ...snowflake.Sequence) *ScriptedSequenceProvider {
+	return &ScriptedSequenceProvider{sequences: sequences}
+}
+
+func (s *ScriptedSequenceProvider) Sequence() snowflake.Sequence {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.consumed >= len(s.sequences) {
+		return Fail(ErrScriptExhausted)
+	}
+	r := s.sequences[s.consumed]
+	s.consumed++
+	return r
+}
+
+// Append adds sequences to the end of the script
+func (s *ScriptedSequenceProvider) Append(sequences ...snowflake.Sequence) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sequences = append(s.sequences, sequences...)
+}
+
+// Remaining returns the number of sequences which have not been consumed yet
+func (s *ScriptedSequenceProvider) Remaining() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.sequences) - s.consumed
+}
@@ -0,0 +1,42 @@
+package snowflaketest
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"testing"
+)
+
+func TestScriptedSequenceProvider(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	boom := errors.New("boom")
+
+	testInstance := NewScriptedSequenceProvider(Ok(10, 1), Fail(boom))
+	assert.That(testInstance.Remaining(), is.EqualTo(2))
+
+	assert.That(testInstance.Sequence(), is.EqualTo(snowflake.Sequence{Seconds: 10, Iteration: 1}))
+	assert.That(testInstance.Sequence().Error, is.EqualTo(boom))
+	assert.That(testInstance.Sequence().Error, is.EqualTo(ErrScriptExhausted))
+
+	testInstance.Append(Ok(11, 1))
+	assert.That(testInstance.Sequence(), is.EqualTo(snowflake.Sequence{Seconds: 11, Iteration: 1}))
+	assert.That(testInstance.Remaining(), is.EqualTo(0))
+}
+
+func TestScriptedSequenceProvider_Generator(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+
+	gen, err := snowflake.NewGenerator(
+		snowflake.WithSequenceProvider(NewScriptedSequenceProvider(Ok(1647619145, 1), Fail(snowflake.ErrClockNotMonotonic))),
+		snowflake.WithNodeID(128),
+	)
+	assert.That(err, is.Nil())
+
+	r, err := gen.Next()
+	assert.That(err, is.Nil())
+	assert.That(r.ID(), is.EqualTo(uint64(6910615572447233)))
+
+	_, err = gen.Next()
+	assert.That(err, is.EqualTo(snowflake.ErrClockNotMonotonic))
+}
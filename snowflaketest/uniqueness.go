@@ -0,0 +1,135 @@
+package snowflaketest
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-snowflake"
+	"sync"
+	"testing"
+)
+
+// ViolationKind describes what went wrong
+type ViolationKind uint8
+
+const (
+	// Duplicate means the ID has been returned before by any attached generator
+	Duplicate ViolationKind = iota
+	// OutOfOrder means the ID is not greater than the previous ID of the same attached generator
+	OutOfOrder
+)
+
+func (v ViolationKind) String() string {
+	if v == Duplicate {
+		return "duplicate"
+	}
+	return "out of order"
+}
+
+// Violation is a single ID which broke uniqueness or order
+type Violation struct {
+	Kind     ViolationKind
+	ID       uint64
+	Previous uint64
+}
+
+func (v Violation) String() string {
+	if v.Kind == Duplicate {
+		return fmt.Sprintf("duplicate id %d", v.ID)
+	}
+	return fmt.Sprintf("id %d is not greater than previous id %d", v.ID, v.Previous)
+}
+
+// UniquenessChecker tracks the IDs of all attached generators and records duplicates across all of them and
+// IDs which are not strictly increasing per attached generator
+type UniquenessChecker struct {
+	lock       sync.Mutex
+	seen       map[uint64]struct{}
+	violations []Violation
+}
+
+// NewUniquenessChecker returns a checker without any attached generator
+func NewUniquenessChecker() *UniquenessChecker {
+	return &UniquenessChecker{seen: map[uint64]struct{}{}}
+}
+
+// Attach returns a generator which checks every ID of gen. Calls to Next of the returned generator are serialized
+// so that the order check reflects the order in which gen issued the IDs
+func (u *UniquenessChecker) Attach(gen snowflake.Generator) snowflake.Generator {
+	return &checkedGenerator{gen: gen, checker: u}
+}
+
+// Count returns the number of distinct IDs seen
+func (u *UniquenessChecker) Count() int {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	return len(u.seen)
+}
+
+// Violations returns all violations in the order they were detected
+func (u *UniquenessChecker) Violations() []Violation {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	return append([]Violation(nil), u.violations...)
+}
+
+// Err returns an error describing the first violation or nil
+func (u *UniquenessChecker) Err() error {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if len(u.violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s (%d violations in total)", u.violations[0], len(u.violations))
+}
+
+// Check fails t for every violation
+func (u *UniquenessChecker) Check(t testing.TB) {
+	t.Helper()
+	for _, v := range u.Violations() {
+		t.Errorf("%s", v)
+	}
+}
+
+func (u *UniquenessChecker) observe(id uint64, previous uint64, hasPrevious bool) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if _, found := u.seen[id]; found {
+		u.violations = append(u.violations, Violation{Kind: Duplicate, ID: id})
+	}
+	u.seen[id] = struct{}{}
+
+	if hasPrevious && id <= previous {
+		u.violations = append(u.violations, Violation{Kind: OutOfOrder, ID: id, Previous: previous})
+	}
+}
+
+type checkedGenerator struct {
+	gen     snowflake.Generator
+	checker *UniquenessChecker
+
+	lock        sync.Mutex
+	previous    uint64
+	hasPrevious bool
+}
+
+func (c *checkedGenerator) Next() (snowflake.ID, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	id, err := c.gen.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	c.checker.observe(id.ID(), c.previous, c.hasPrevious)
+	c.previous, c.hasPrevious = id.ID(), true
+	return id, nil
+}
+
+func (c *checkedGenerator) MustNext() snowflake.ID {
+	if r, err := c.Next(); err != nil {
+		panic(err)
+	} else {
+		return r
+	}
+}
@@ -0,0 +1,64 @@
+package snowflaketest
+
+import (
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"sync"
+	"testing"
+)
+
+func TestUniquenessChecker(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		testInstance := NewUniquenessChecker()
+
+		clock := NewManualClock(10)
+		gen1 := testInstance.Attach(snowflake.MustNewGenerator(snowflake.WithClock(clock), snowflake.WithNodeID(1)))
+		gen2 := testInstance.Attach(snowflake.MustNewGenerator(snowflake.WithClock(clock), snowflake.WithNodeID(2)))
+
+		wg := sync.WaitGroup{}
+		for _, gen := range []snowflake.Generator{gen1, gen2} {
+			wg.Add(1)
+			go func(gen snowflake.Generator) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					gen.MustNext()
+				}
+			}(gen)
+		}
+		wg.Wait()
+
+		assert.That(testInstance.Count(), is.EqualTo(200))
+		assert.That(len(testInstance.Violations()), is.EqualTo(0))
+		assert.That(testInstance.Err(), is.Nil())
+		testInstance.Check(t)
+	})
+	t.Run("duplicate across generators", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		testInstance := NewUniquenessChecker()
+
+		clock := NewManualClock(10)
+		gen1 := testInstance.Attach(snowflake.MustNewGenerator(snowflake.WithClock(clock)))
+		gen2 := testInstance.Attach(snowflake.MustNewGenerator(snowflake.WithClock(clock)))
+
+		id := gen1.MustNext()
+		gen2.MustNext()
+
+		assert.That(testInstance.Violations(), is.EqualTo([]Violation{{Kind: Duplicate, ID: id.ID()}}))
+		assert.That(testInstance.Err().Error(), is.EqualTo("duplicate id "+id.String()+" (1 violations in total)"))
+	})
+	t.Run("out of order", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		testInstance := NewUniquenessChecker()
+
+		gen := testInstance.Attach(snowflake.MustNewGenerator(
+			snowflake.WithSequenceProvider(NewScriptedSequenceProvider(Ok(10, 2), Ok(10, 1))),
+		))
+		first := gen.MustNext()
+		second := gen.MustNext()
+
+		assert.That(testInstance.Violations(), is.EqualTo([]Violation{{Kind: OutOfOrder, ID: second.ID(), Previous: first.ID()}}))
+		assert.That(testInstance.Violations()[0].Kind.String(), is.EqualTo("out of order"))
+	})
+}
@@ -0,0 +1,296 @@
+package snowflake
+
+import (
+	"fmt"
+	"github.com/scarabsoft/go-snowflake/internal"
+	"math"
+	"time"
+)
+
+// Variant describes the snowflake format of another system: its epoch, the unit of its timestamp and its bits.
+// Its node may use up to 16 bits, the top bits may be left unused and SequenceFirst stores the sequence above the node
+type Variant struct {
+	Name          string
+	Epoch         time.Time
+	Unit          time.Duration
+	TimestampBits uint8
+	NodeBits      uint8
+	SequenceBits  uint8
+	SequenceFirst bool
+}
+
+var (
+	// TwitterVariant is |-1 unused-|-41 ms-|-5 datacenter, 5 worker-|-12 sequence-|
+	TwitterVariant = Variant{Name: "twitter", Epoch: TwitterEpoch, Unit: time.Millisecond, TimestampBits: 41, NodeBits: 10, SequenceBits: 12}
+	// DiscordVariant is |-42 ms-|-5 worker, 5 process-|-12 increment-|
+	DiscordVariant = Variant{Name: "discord", Epoch: DiscordEpoch, Unit: time.Millisecond, TimestampBits: 42, NodeBits: 10, SequenceBits: 12}
+	// SonyflakeVariant is |-1 unused-|-39 10ms-|-8 sequence-|-16 machine-|
+	SonyflakeVariant = Variant{Name: "sonyflake", Epoch: SonyflakeEpoch, Unit: 10 * time.Millisecond, TimestampBits: 39, NodeBits: 16, SequenceBits: 8, SequenceFirst: true}
+	// InstagramVariant is |-41 ms-|-13 shard-|-10 sequence-|
+	InstagramVariant = Variant{Name: "instagram", Epoch: InstagramEpoch, Unit: time.Millisecond, TimestampBits: 41, NodeBits: 13, SequenceBits: 10}
+)
+
+// Validate checks the bits of the variant and that its unit converts into seconds without loss
+func (v Variant) Validate() error {
+	if err := v.internal().Validate(); err != nil {
+		return err
+	}
+	if v.Unit <= 0 || (time.Second%v.Unit != 0 && v.Unit%time.Second != 0) {
+		return ErrUnitInvalid
+	}
+	return nil
+}
+
+// MaxNodeID returns the largest node id of the variant
+func (v Variant) MaxNodeID() uint16 {
+	return v.internal().MaxNodeID()
+}
+
+// MaxSequence returns the largest sequence of the variant
+func (v Variant) MaxSequence() uint16 {
+	return v.internal().MaxSequence()
+}
+
+// Horizon returns the date at which the variant runs out of timestamp bits
+func (v Variant) Horizon() time.Time {
+	return v.time(v.internal().MaxTimestamp())
+}
+
+// Decode returns the ID of the variant, so IDs of the other system can be read like IDs of this package
+func (v Variant) Decode(id uint64) ExtendedID {
+	return &variantIDImpl{id, v}
+}
+
+// Encode returns the ID generated at t by the given node and sequence, t is truncated to the unit of the variant
+func (v Variant) Encode(t time.Time, nodeID uint16, sequence uint16) (ExtendedID, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	if t.Before(v.Epoch) {
+		return nil, ErrTimeBeforeEpoch
+	}
+
+	id, err := v.internal().Pack(v.ticks(t), nodeID, sequence)
+	if err != nil {
+		return nil, err
+	}
+	return v.Decode(id), nil
+}
+
+func (v Variant) String() string {
+	return fmt.Sprintf("%s %d:%d:%d", v.Name, v.TimestampBits, v.NodeBits, v.SequenceBits)
+}
+
+func (v Variant) internal() internal.Variant {
+	return internal.Variant{
+		TimestampBits: v.TimestampBits,
+		NodeBits:      v.NodeBits,
+		SequenceBits:  v.SequenceBits,
+		SequenceFirst: v.SequenceFirst,
+	}
+}
+
+// ticks returns the units passed between the epoch and t, t must not be before the epoch. It counts seconds and
+// nanoseconds separately so wide timestamps do not overflow durations, ticks beyond uint64 saturate
+func (v Variant) ticks(t time.Time) uint64 {
+	seconds := uint64(t.Unix() - v.Epoch.Unix())
+	nanos := int64(t.Nanosecond()) - int64(v.Epoch.Nanosecond())
+	if nanos < 0 {
+		seconds--
+		nanos += int64(time.Second)
+	}
+
+	if v.Unit >= time.Second {
+		return seconds / uint64(v.Unit/time.Second)
+	}
+	perSecond := uint64(time.Second / v.Unit)
+	if seconds > (math.MaxUint64-perSecond)/perSecond {
+		return math.MaxUint64
+	}
+	return seconds*perSecond + uint64(time.Duration(nanos)/v.Unit)
+}
+
+// seconds returns the whole seconds and the remainder of ticks units, seconds beyond uint64 saturate
+func (v Variant) seconds(ticks uint64) (uint64, time.Duration) {
+	if v.Unit >= time.Second {
+		perTick := uint64(v.Unit / time.Second)
+		if ticks > math.MaxUint64/perTick {
+			return math.MaxUint64, 0
+		}
+		return ticks * perTick, 0
+	}
+	perSecond := uint64(time.Second / v.Unit)
+	return ticks / perSecond, time.Duration(ticks%perSecond) * v.Unit
+}
+
+// time returns the time ticks units after the epoch, times beyond the range of time.Unix saturate like Layout.Horizon
+func (v Variant) time(ticks uint64) time.Time {
+	seconds, rest := v.seconds(ticks)
+	epoch := v.Epoch.Unix()
+	if seconds > math.MaxInt64 || (epoch > 0 && int64(seconds) > math.MaxInt64-epoch) {
+		return time.Unix(math.MaxInt64, 0).UTC()
+	}
+	return time.Unix(epoch+int64(seconds), int64(v.Epoch.Nanosecond())+int64(rest)).UTC()
+}
+
+type variantIDImpl struct {
+	id      uint64
+	variant Variant
+}
+
+func (i variantIDImpl) ID() uint64 {
+	return i.id
+}
+
+func (i variantIDImpl) Int64() int64 {
+	return int64(i.id)
+}
+
+func (i variantIDImpl) Weeks() uint64 {
+	return i.Days() / 7
+}
+
+func (i variantIDImpl) Days() uint64 {
+	return i.Hours() / 24
+}
+
+func (i variantIDImpl) Hours() uint64 {
+	return i.Minutes() / 60
+}
+
+func (i variantIDImpl) Minutes() uint64 {
+	return i.Seconds() / 60
+}
+
+// Seconds returns the whole seconds passed since the epoch of the variant
+func (i variantIDImpl) Seconds() uint64 {
+	ticks, _, _ := i.variant.internal().Decode(i.id)
+	seconds, _ := i.variant.seconds(ticks)
+	return seconds
+}
+
+func (i variantIDImpl) NodeID() uint8 {
+	return uint8(i.Node())
+}
+
+func (i variantIDImpl) Node() uint16 {
+	_, nodeID, _ := i.variant.internal().Decode(i.id)
+	return nodeID
+}
+
+func (i variantIDImpl) Iteration() uint16 {
+	_, _, sequence := i.variant.internal().Decode(i.id)
+	return sequence
+}
+
+// Field returns false, variants have no named fields
+func (i variantIDImpl) Field(string) (uint8, bool) {
+	return 0, false
+}
+
+// Time returns the time the ID was generated at in the unit of the variant
+func (i variantIDImpl) Time() time.Time {
+	ticks, _, _ := i.variant.internal().Decode(i.id)
+	return i.variant.time(ticks)
+}
+
+func (i variantIDImpl) String() string {
+	return fmt.Sprintf("%d", i.ID())
+}
+
+type variantBuilderImpl struct {
+	variant Variant
+	now     func() time.Time
+}
+
+type VariantOption func(*variantBuilderImpl) error
+
+// WithVariantEpoch replaces the epoch of the variant, like the StartTime of Sonyflake or the epoch of an own Instagram like scheme
+func WithVariantEpoch(epoch time.Time) VariantOption {
+	return func(impl *variantBuilderImpl) error {
+		impl.variant.Epoch = epoch
+		return nil
+	}
+}
+
+// WithVariantNow sets the source of the current time. By default time.Now
+func WithVariantNow(now func() time.Time) VariantOption {
+	return func(impl *variantBuilderImpl) error {
+		impl.now = now
+		return nil
+	}
+}
+
+// NewVariantGenerator returns a generator producing IDs of variant for the given node
+func NewVariantGenerator(variant Variant, nodeID uint16, options ...VariantOption) (Generator, error) {
+	r := &variantBuilderImpl{
+		variant: variant,
+		now:     time.Now,
+	}
+
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.variant.Validate(); err != nil {
+		return nil, err
+	}
+	if r.variant.Epoch.After(r.now()) {
+		return nil, ErrEpochInFuture
+	}
+
+	seqProvider := internal.NewTickSequenceProvider(
+		internal.NewTickClock(r.variant.Epoch, r.variant.Unit, r.now),
+		r.variant.MaxSequence(),
+		r.variant.Unit,
+	)
+
+	gen, err := internal.NewVariantGenerator(seqProvider, nodeID, r.variant.internal())
+	if err != nil {
+		return nil, err
+	}
+	return &variantGeneratorImpl{gen: gen, variant: r.variant}, nil
+}
+
+type variantGeneratorImpl struct {
+	gen     internal.SnowflakeGenerator
+	variant Variant
+}
+
+func (g *variantGeneratorImpl) Next() (ID, error) {
+	r, err := g.gen.Next()
+	if err != nil {
+		return nil, err
+	}
+	return g.variant.Decode(r), nil
+}
+
+func (g *variantGeneratorImpl) MustNext() ID {
+	if r, err := g.Next(); err != nil {
+		panic(err)
+	} else {
+		return r
+	}
+}
+
+// NewTwitterCompatible returns a generator of Twitter snowflakes, nodeID is the datacenter << 5 | worker
+func NewTwitterCompatible(nodeID uint16, options ...VariantOption) (Generator, error) {
+	return NewVariantGenerator(TwitterVariant, nodeID, options...)
+}
+
+// NewDiscordCompatible returns a generator of Discord snowflakes, nodeID is the worker << 5 | process
+func NewDiscordCompatible(nodeID uint16, options ...VariantOption) (Generator, error) {
+	return NewVariantGenerator(DiscordVariant, nodeID, options...)
+}
+
+// NewSonyflakeCompatible returns a generator of Sonyflake IDs, nodeID is the 16 bit machine id
+func NewSonyflakeCompatible(nodeID uint16, options ...VariantOption) (Generator, error) {
+	return NewVariantGenerator(SonyflakeVariant, nodeID, options...)
+}
+
+// NewInstagramCompatible returns a generator of Instagram IDs, nodeID is the 13 bit logical shard
+func NewInstagramCompatible(nodeID uint16, options ...VariantOption) (Generator, error) {
+	return NewVariantGenerator(InstagramVariant, nodeID, options...)
+}
@@ -0,0 +1,254 @@
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/scarabsoft/go-snowflake"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBlockSize is the number of IDs the client fetches at once
+	DefaultBlockSize = 256
+)
+
+var (
+	ErrBlockSizeOutOfRange = errors.New("block size must be greater than 0 and fit into a frame")
+	ErrPrefetchOutOfRange  = errors.New("prefetch mark must be less than the block size")
+)
+
+// Client is a Generator which fetches blocks of IDs from a Server and hands them out one by one
+// The connection gets closed on the first transport error, a new client has to be dialed afterwards
+type Client struct {
+	conn        net.Conn
+	blockSize   uint32
+	prefetchAt  uint32
+	prefetchSet bool
+	codec       snowflake.Codec
+
+	// connLock serializes the requests on conn, err is the transport error which closed it
+	connLock sync.Mutex
+	err      error
+
+	// lock guards the buffer, prefetched is closed once the block fetched in the background arrived
+	lock        sync.Mutex
+	buffer      []uint64
+	prefetched  chan struct{}
+	prefetchErr error
+	closed      bool
+}
+
+type ClientOption func(*Client) error
+
+// WithBlockSize sets the number of IDs fetched at once, must not exceed the max batch of the server.
+// By default DefaultBlockSize
+func WithBlockSize(blockSize uint32) ClientOption {
+	return func(c *Client) error {
+		if blockSize == 0 || blockSize > maxCount {
+			return ErrBlockSizeOutOfRange
+		}
+		c.blockSize = blockSize
+		return nil
+	}
+}
+
+// WithPrefetchAt sets the number of buffered IDs at which Next fetches the next block in the background, must be
+// less than the block size. By default a quarter of the block size
+func WithPrefetchAt(prefetchAt uint32) ClientOption {
+	return func(c *Client) error {
+		c.prefetchAt = prefetchAt
+		c.prefetchSet = true
+		return nil
+	}
+}
+
+// WithLayout sets the layout used to decode fetched IDs counting from the UNIX epoch, must match the generator of
+// the server. See WithCodec for servers using another epoch. By default DefaultLayout
+func WithLayout(layout snowflake.Layout) ClientOption {
+	return func(c *Client) error {
+		if err := layout.Validate(); err != nil {
+			return err
+		}
+		c.codec.Layout = layout
+		return nil
+	}
+}
+
+// WithCodec sets the codec used to decode fetched IDs, e.g. the snowflake.NewCodec of the options of the generator
+// of the server. By default DefaultLayout and the UNIX epoch
+func WithCodec(codec snowflake.Codec) ClientOption {
+	return func(c *Client) error {
+		if err := codec.Layout.Validate(); err != nil {
+			return err
+		}
+		c.codec = codec
+		return nil
+	}
+}
+
+// Dial connects to a Server listening on network and address, e.g. tcp and localhost:4242 or unix and /run/snowflake.sock
+func Dial(network, address string, options ...ClientOption) (*Client, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := NewClient(conn, options...)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewClient returns a new client using an established connection and apply the requested options
+func NewClient(conn net.Conn, options ...ClientOption) (*Client, error) {
+	r := &Client{
+		conn:      conn,
+		blockSize: DefaultBlockSize,
+		codec:     snowflake.Codec{Epoch: time.Unix(0, 0).UTC(), Layout: snowflake.DefaultLayout},
+	}
+
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if !r.prefetchSet {
+		r.prefetchAt = r.blockSize / 4
+	}
+	if r.prefetchAt >= r.blockSize {
+		return nil, ErrPrefetchOutOfRange
+	}
+	return r, nil
+}
+
+// Next returns the next buffered ID. Once the buffer drops to the prefetch mark the next block is fetched in the
+// background, Next only waits for it if the buffer ran empty. A failed prefetch is returned by the following Next
+// which finds the buffer empty
+func (c *Client) Next() (snowflake.ID, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for len(c.buffer) == 0 {
+		if c.closed {
+			return nil, net.ErrClosed
+		}
+		if c.prefetched == nil {
+			if err := c.prefetchErr; err != nil {
+				c.prefetchErr = nil
+				return nil, err
+			}
+			c.prefetch()
+		}
+
+		prefetched := c.prefetched
+		c.lock.Unlock()
+		<-prefetched
+		c.lock.Lock()
+	}
+
+	id := c.buffer[0]
+	c.buffer = c.buffer[1:]
+	if uint32(len(c.buffer)) <= c.prefetchAt && c.prefetched == nil && c.prefetchErr == nil {
+		c.prefetch()
+	}
+	return c.codec.Decode(id), nil
+}
+
+// prefetch fetches the next block in the background, c.lock has to be held
+func (c *Client) prefetch() {
+	prefetched := make(chan struct{})
+	c.prefetched = prefetched
+
+	go func() {
+		ids, err := c.Fetch(c.blockSize)
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		if !c.closed {
+			c.buffer = append(c.buffer, ids...)
+			c.prefetchErr = err
+		}
+		c.prefetched = nil
+		close(prefetched)
+	}()
+}
+
+func (c *Client) MustNext() snowflake.ID {
+	if r, err := c.Next(); err != nil {
+		panic(err)
+	} else {
+		return r
+	}
+}
+
+// Fetch requests count IDs from the server bypassing the buffer
+func (c *Client) Fetch(count uint32) ([]uint64, error) {
+	if count == 0 || count > maxCount {
+		return nil, ErrInvalidCount
+	}
+
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+	return c.fetch(count)
+}
+
+// Block implements snowflake.BlockSource so a client can be wrapped by snowflake.NewBufferedRemoteGenerator
+func (c *Client) Block(n int) ([]uint64, error) {
+	if n <= 0 || uint64(n) > maxCount {
+		return nil, ErrInvalidCount
+	}
+	return c.Fetch(uint32(n))
+}
+
+// Close closes the connection, buffered IDs are discarded
+func (c *Client) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.buffer = nil
+	c.closed = true
+	return c.conn.Close()
+}
+
+func (c *Client) fetch(count uint32) ([]uint64, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	var request [requestSize]byte
+	binary.BigEndian.PutUint32(request[:], count)
+	if err := writeFrame(c.conn, request[:]); err != nil {
+		return nil, c.fail(err)
+	}
+
+	maxSize := 1 + uint64(count)*idSize
+	if maxSize < 1+maxErrorSize {
+		maxSize = 1 + maxErrorSize
+	}
+	if maxSize > math.MaxUint32 {
+		maxSize = math.MaxUint32
+	}
+
+	payload, err := readFrame(c.conn, uint32(maxSize))
+	if err != nil {
+		return nil, c.fail(err)
+	}
+
+	ids, err := decodeResponse(payload, count)
+	if errors.Is(err, ErrMalformedResponse) {
+		return nil, c.fail(err)
+	}
+	return ids, err
+}
+
+// fail closes the connection as the stream can not be trusted to be in sync anymore
+func (c *Client) fail(err error) error {
+	c.err = err
+	_ = c.conn.Close()
+	return err
+}
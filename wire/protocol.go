@@ -0,0 +1,117 @@
+// Package wire serves a snowflake.Generator over a length-prefixed binary protocol on TCP or Unix sockets
+//
+// Every message is a frame consisting of a big-endian uint32 length followed by length bytes of payload.
+//
+// Request payload:   |--uint32 count--|
+// Response payload:  |--uint8 status--|--count big-endian uint64 IDs if status is StatusOK, error message otherwise--|
+//
+// A connection can be used for any number of sequential requests.
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/scarabsoft/go-snowflake"
+	"io"
+	"math"
+)
+
+// Status is the first byte of every response
+type Status uint8
+
+const (
+	StatusOK Status = iota
+	StatusClockNotMonotonic
+	StatusInvalidCount
+	StatusMalformedRequest
+	StatusInternal
+)
+
+const (
+	requestSize = 4
+	idSize      = 8
+	// maxErrorSize caps the error message of a response
+	maxErrorSize = 1024
+	// maxCount is the largest count whose response fits into a frame
+	maxCount = (math.MaxUint32 - 1) / idSize
+)
+
+var (
+	ErrInvalidCount      = errors.New("count must be between 1 and the max batch of the server")
+	ErrMalformedRequest  = errors.New("malformed request")
+	ErrInternal          = errors.New("internal server error")
+	ErrFrameTooLarge     = errors.New("frame exceeds the max size")
+	ErrMalformedResponse = errors.New("malformed response")
+)
+
+func writeFrame(w io.Writer, payload []byte) error {
+	frame := make([]byte, 4+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	copy(frame[4:], payload)
+	_, err := w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader, maxSize uint32) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxSize {
+		return nil, ErrFrameTooLarge
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func encodeIDs(ids []uint64) []byte {
+	r := make([]byte, 1+len(ids)*idSize)
+	r[0] = byte(StatusOK)
+	for i, id := range ids {
+		binary.BigEndian.PutUint64(r[1+i*idSize:], id)
+	}
+	return r
+}
+
+func encodeError(status Status, err error) []byte {
+	msg := err.Error()
+	if len(msg) > maxErrorSize {
+		msg = msg[:maxErrorSize]
+	}
+	return append([]byte{byte(status)}, msg...)
+}
+
+// decodeResponse returns the IDs of a response or the error it carries
+func decodeResponse(payload []byte, count uint32) ([]uint64, error) {
+	if len(payload) == 0 {
+		return nil, ErrMalformedResponse
+	}
+
+	status, body := Status(payload[0]), payload[1:]
+	switch status {
+	case StatusOK:
+		if uint32(len(body)) != count*idSize {
+			return nil, ErrMalformedResponse
+		}
+		r := make([]uint64, count)
+		for i := range r {
+			r[i] = binary.BigEndian.Uint64(body[i*idSize:])
+		}
+		return r, nil
+	case StatusClockNotMonotonic:
+		return nil, snowflake.ErrClockNotMonotonic
+	case StatusInvalidCount:
+		return nil, ErrInvalidCount
+	case StatusMalformedRequest:
+		return nil, ErrMalformedRequest
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInternal, body)
+	}
+}
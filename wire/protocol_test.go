@@ -0,0 +1,59 @@
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"testing"
+)
+
+func TestFrame(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		buf := &bytes.Buffer{}
+		assert.That(writeFrame(buf, []byte{1, 2, 3}), is.Nil())
+		assert.That(buf.Bytes(), is.EqualTo([]byte{0, 0, 0, 3, 1, 2, 3}))
+
+		r, err := readFrame(buf, 3)
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo([]byte{1, 2, 3}))
+	})
+	t.Run("too large", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := readFrame(bytes.NewReader([]byte{0, 0, 0, 5, 1, 2, 3, 4, 5}), 4)
+		assert.That(err, is.EqualTo(ErrFrameTooLarge))
+	})
+}
+
+func TestDecodeResponse(t *testing.T) {
+	t.Run("ids", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		r, err := decodeResponse(encodeIDs([]uint64{1, 6910615572447233}), 2)
+		assert.That(err, is.Nil())
+		assert.That(r, is.EqualTo([]uint64{1, 6910615572447233}))
+	})
+	t.Run("count mismatch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := decodeResponse(encodeIDs([]uint64{1}), 2)
+		assert.That(err, is.EqualTo(ErrMalformedResponse))
+	})
+	t.Run("empty", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := decodeResponse(nil, 1)
+		assert.That(err, is.EqualTo(ErrMalformedResponse))
+	})
+	t.Run("errors", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		_, err := decodeResponse(encodeError(StatusClockNotMonotonic, snowflake.ErrClockNotMonotonic), 1)
+		assert.That(err, is.EqualTo(snowflake.ErrClockNotMonotonic))
+
+		_, err = decodeResponse(encodeError(StatusInvalidCount, ErrInvalidCount), 1)
+		assert.That(err, is.EqualTo(ErrInvalidCount))
+
+		_, err = decodeResponse(encodeError(StatusInternal, errors.New("boom")), 1)
+		assert.That(errors.Is(err, ErrInternal), is.True())
+		assert.That(err.Error(), is.EqualTo("internal server error: boom"))
+	})
+}
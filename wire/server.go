@@ -0,0 +1,181 @@
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/scarabsoft/go-snowflake"
+	"net"
+	"sync"
+)
+
+const (
+	// DefaultMaxBatch is the max number of IDs a single request can ask for
+	DefaultMaxBatch = 4096
+)
+
+var (
+	ErrMaxBatchOutOfRange = errors.New("max batch must be greater than 0 and fit into a frame")
+	ErrServerClosed       = errors.New("server closed")
+)
+
+// Server issues IDs of a Generator to every client connected to one of its listeners
+type Server struct {
+	gen      snowflake.Generator
+	maxBatch uint32
+
+	lock      sync.Mutex
+	closed    bool
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	wg        sync.WaitGroup
+}
+
+type ServerOption func(*Server) error
+
+// WithMaxBatch sets the max number of IDs a single request can ask for, the response has to fit into a frame.
+// By default DefaultMaxBatch
+func WithMaxBatch(maxBatch uint32) ServerOption {
+	return func(s *Server) error {
+		if maxBatch == 0 || maxBatch > maxCount {
+			return ErrMaxBatchOutOfRange
+		}
+		s.maxBatch = maxBatch
+		return nil
+	}
+}
+
+// NewServer returns a new server issuing IDs of gen and apply the requested options
+func NewServer(gen snowflake.Generator, options ...ServerOption) (*Server, error) {
+	r := &Server{
+		gen:       gen,
+		maxBatch:  DefaultMaxBatch,
+		listeners: map[net.Listener]struct{}{},
+		conns:     map[net.Conn]struct{}{},
+	}
+
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Serve accepts connections on l until the server gets closed, it always returns a non nil error
+func (s *Server) Serve(l net.Listener) error {
+	if !s.track(l) {
+		return ErrServerClosed
+	}
+	defer s.untrack(l)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.lock.Lock()
+		if s.closed {
+			s.lock.Unlock()
+			_ = conn.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.lock.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+// Close stops all listeners, closes all connections and waits until their handlers returned
+func (s *Server) Close() error {
+	s.lock.Lock()
+	s.closed = true
+	var err error
+	for l := range s.listeners {
+		if closeErr := l.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+		s.lock.Lock()
+		delete(s.conns, conn)
+		s.lock.Unlock()
+		s.wg.Done()
+	}()
+
+	for {
+		payload, err := readFrame(conn, requestSize)
+		if err != nil {
+			if errors.Is(err, ErrFrameTooLarge) {
+				_ = writeFrame(conn, encodeError(StatusMalformedRequest, ErrMalformedRequest))
+			}
+			return
+		}
+
+		if err := writeFrame(conn, s.respond(payload)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) respond(payload []byte) []byte {
+	if len(payload) != requestSize {
+		return encodeError(StatusMalformedRequest, ErrMalformedRequest)
+	}
+
+	count := binary.BigEndian.Uint32(payload)
+	if count == 0 || count > s.maxBatch {
+		return encodeError(StatusInvalidCount, ErrInvalidCount)
+	}
+
+	ids := make([]uint64, count)
+	for i := range ids {
+		id, err := s.gen.Next()
+		if err != nil {
+			if errors.Is(err, snowflake.ErrClockNotMonotonic) {
+				return encodeError(StatusClockNotMonotonic, err)
+			}
+			return encodeError(StatusInternal, err)
+		}
+		ids[i] = id.ID()
+	}
+	return encodeIDs(ids)
+}
+
+func (s *Server) track(l net.Listener) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return false
+	}
+	s.listeners[l] = struct{}{}
+	return true
+}
+
+func (s *Server) untrack(l net.Listener) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.listeners, l)
+}
+
+func (s *Server) isClosed() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.closed
+}
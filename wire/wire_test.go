@@ -0,0 +1,255 @@
+package wire
+
+import (
+	"errors"
+	"github.com/scarabsoft/go-hamcrest"
+	"github.com/scarabsoft/go-hamcrest/is"
+	"github.com/scarabsoft/go-snowflake"
+	"github.com/scarabsoft/go-snowflake/snowflaketest"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type failingGenerator struct {
+	snowflake.Generator
+	err error
+}
+
+func (f *failingGenerator) Next() (snowflake.ID, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.Generator.Next()
+}
+
+func startServer(t *testing.T, network, address string, options ...ServerOption) (net.Addr, *failingGenerator) {
+	gen := &failingGenerator{Generator: snowflake.MustNewGenerator(
+		snowflake.WithClock(snowflaketest.NewManualClock(1647619145)),
+		snowflake.WithNodeID(128),
+	)}
+
+	s, err := NewServer(gen, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(l)
+	}()
+
+	t.Cleanup(func() {
+		_ = s.Close()
+		if err := <-done; err != ErrServerClosed {
+			t.Errorf("unexpected serve error %v", err)
+		}
+	})
+	return l.Addr(), gen
+}
+
+func TestNewServer(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	_, err := NewServer(snowflake.MustNewGenerator(), WithMaxBatch(0))
+	assert.That(err, is.EqualTo(ErrMaxBatchOutOfRange))
+
+	_, err = NewServer(snowflake.MustNewGenerator(), WithMaxBatch(maxCount+1))
+	assert.That(err, is.EqualTo(ErrMaxBatchOutOfRange))
+
+	_, err = NewServer(snowflake.MustNewGenerator(), WithMaxBatch(maxCount))
+	assert.That(err, is.Nil())
+}
+
+func TestNewClient(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	_, err := NewClient(nil, WithBlockSize(0))
+	assert.That(err, is.EqualTo(ErrBlockSizeOutOfRange))
+
+	_, err = NewClient(nil, WithBlockSize(maxCount+1))
+	assert.That(err, is.EqualTo(ErrBlockSizeOutOfRange))
+
+	_, err = NewClient(nil, WithBlockSize(3), WithPrefetchAt(3))
+	assert.That(err, is.EqualTo(ErrPrefetchOutOfRange))
+}
+
+func TestClient_Next(t *testing.T) {
+	t.Run("tcp", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		addr, _ := startServer(t, "tcp", "127.0.0.1:0")
+
+		client, err := Dial(addr.Network(), addr.String(), WithBlockSize(2))
+		assert.That(err, is.Nil())
+		defer client.Close()
+
+		var _ snowflake.Generator = client
+		for i := 1; i <= 5; i++ {
+			id, err := client.Next()
+			assert.That(err, is.Nil())
+			assert.That(id.ID(), is.EqualTo(uint64(6910615572447232+i)))
+			assert.That(id.NodeID(), is.EqualTo(uint8(128)))
+			assert.That(id.Iteration(), is.EqualTo(uint16(i)))
+		}
+	})
+	t.Run("unix", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		addr, _ := startServer(t, "unix", filepath.Join(t.TempDir(), "snowflake.sock"))
+
+		client, err := Dial(addr.Network(), addr.String())
+		assert.That(err, is.Nil())
+		defer client.Close()
+
+		assert.That(client.MustNext().ID(), is.EqualTo(uint64(6910615572447233)))
+	})
+	t.Run("prefetches blocks", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		addr, gen := startServer(t, "tcp", "127.0.0.1:0")
+
+		client, err := Dial(addr.Network(), addr.String(), WithBlockSize(3))
+		assert.That(err, is.Nil())
+		defer client.Close()
+
+		_, err = client.Next()
+		assert.That(err, is.Nil())
+
+		gen.err = snowflake.ErrClockNotMonotonic
+		for i := 0; i < 2; i++ {
+			_, err = client.Next()
+			assert.That(err, is.Nil())
+		}
+		_, err = client.Next()
+		assert.That(err, is.EqualTo(snowflake.ErrClockNotMonotonic))
+
+		gen.err = nil
+		id, err := client.Next()
+		assert.That(err, is.Nil())
+		assert.That(id.Iteration(), is.EqualTo(uint16(4)))
+	})
+	t.Run("prefetches in the background", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		addr, gen := startServer(t, "tcp", "127.0.0.1:0")
+
+		client, err := Dial(addr.Network(), addr.String(), WithBlockSize(4), WithPrefetchAt(2))
+		assert.That(err, is.Nil())
+		defer client.Close()
+
+		for i := 0; i < 2; i++ {
+			_, err = client.Next()
+			assert.That(err, is.Nil())
+		}
+		for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+			client.lock.Lock()
+			buffered := len(client.buffer)
+			client.lock.Unlock()
+			if buffered == 6 || time.Now().After(deadline) {
+				assert.That(buffered, is.EqualTo(6))
+				break
+			}
+		}
+
+		gen.err = snowflake.ErrClockNotMonotonic
+		for i := 3; i <= 8; i++ {
+			id, err := client.Next()
+			assert.That(err, is.Nil())
+			assert.That(id.Iteration(), is.EqualTo(uint16(i)))
+		}
+		_, err = client.Next()
+		assert.That(err, is.EqualTo(snowflake.ErrClockNotMonotonic))
+	})
+	t.Run("codec", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		addr, _ := startServer(t, "tcp", "127.0.0.1:0")
+
+		codec, err := snowflake.NewCodec(snowflake.WithClock(snowflake.NewUnixClockWithEpoch(1000)))
+		assert.That(err, is.Nil())
+		client, err := Dial(addr.Network(), addr.String(), WithCodec(codec))
+		assert.That(err, is.Nil())
+		defer client.Close()
+
+		id := client.MustNext().(snowflake.ExtendedID)
+		assert.That(id.Time(), is.EqualTo(time.Unix(1647619145+1000, 0).UTC()))
+	})
+	t.Run("block size exceeds max batch", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		addr, _ := startServer(t, "tcp", "127.0.0.1:0", WithMaxBatch(2))
+
+		client, err := Dial(addr.Network(), addr.String(), WithBlockSize(3))
+		assert.That(err, is.Nil())
+		defer client.Close()
+
+		_, err = client.Next()
+		assert.That(err, is.EqualTo(ErrInvalidCount))
+	})
+	t.Run("internal error", func(t *testing.T) {
+		assert := hamcrest.NewAssertion(t)
+		addr, gen := startServer(t, "tcp", "127.0.0.1:0")
+		gen.err = errors.New("boom")
+
+		client, err := Dial(addr.Network(), addr.String())
+		assert.That(err, is.Nil())
+		defer client.Close()
+
+		_, err = client.Next()
+		assert.That(errors.Is(err, ErrInternal), is.True())
+	})
+}
+
+func TestClient_Fetch(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	addr, _ := startServer(t, "tcp", "127.0.0.1:0")
+
+	client, err := Dial(addr.Network(), addr.String())
+	assert.That(err, is.Nil())
+	defer client.Close()
+
+	ids, err := client.Fetch(3)
+	assert.That(err, is.Nil())
+	assert.That(ids, is.EqualTo([]uint64{6910615572447233, 6910615572447234, 6910615572447235}))
+
+	for _, n := range []int{0, -1, maxCount + 1} {
+		_, err = client.Block(n)
+		assert.That(err, is.EqualTo(ErrInvalidCount))
+	}
+	_, err = client.Fetch(maxCount + 1)
+	assert.That(err, is.EqualTo(ErrInvalidCount))
+}
+
+func TestServer_MalformedRequest(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	addr, _ := startServer(t, "tcp", "127.0.0.1:0")
+
+	conn, err := net.Dial(addr.Network(), addr.String())
+	assert.That(err, is.Nil())
+	defer conn.Close()
+
+	assert.That(writeFrame(conn, []byte{0, 1}), is.Nil())
+	payload, err := readFrame(conn, 1+maxErrorSize)
+	assert.That(err, is.Nil())
+	_, err = decodeResponse(payload, 1)
+	assert.That(err, is.EqualTo(ErrMalformedRequest))
+}
+
+func TestServer_Close(t *testing.T) {
+	assert := hamcrest.NewAssertion(t)
+	addr, _ := startServer(t, "tcp", "127.0.0.1:0")
+
+	client, err := Dial(addr.Network(), addr.String())
+	assert.That(err, is.Nil())
+	defer client.Close()
+	_, err = client.Next()
+	assert.That(err, is.Nil())
+
+	s, err := NewServer(snowflake.MustNewGenerator())
+	assert.That(err, is.Nil())
+	assert.That(s.Close(), is.Nil())
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.That(err, is.Nil())
+	defer l.Close()
+	assert.That(s.Serve(l), is.EqualTo(ErrServerClosed))
+}